@@ -0,0 +1,101 @@
+package api
+
+import (
+	"tender-backend/config"
+	"tender-backend/gen_proto"
+	grpc_handlers "tender-backend/internal/grpc/handlers"
+	"tender-backend/internal/grpc/interceptor"
+	"tender-backend/internal/http/handlers"
+	"tender-backend/notification"
+	"tender-backend/rate_limiter"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// grpcPolicy lists the methods callable without a token, like the public REST
+// routes, and the methods limited to clients or contractors.
+var grpcPolicy = interceptor.Policy{
+	Public: map[string]bool{
+		gen_proto.UserService_Register_FullMethodName:      true,
+		gen_proto.UserService_Login_FullMethodName:         true,
+		gen_proto.UserService_GetUser_FullMethodName:       true,
+		gen_proto.TenderService_GetTender_FullMethodName:   true,
+		gen_proto.TenderService_ListTenders_FullMethodName: true,
+		gen_proto.BidService_GetBid_FullMethodName:         true,
+	},
+	Roles: map[string]string{
+		gen_proto.TenderService_CreateTender_FullMethodName:       "client",
+		gen_proto.TenderService_UpdateTenderStatus_FullMethodName: "client",
+		gen_proto.TenderService_DeleteTender_FullMethodName:       "client",
+		gen_proto.TenderService_AwardTender_FullMethodName:        "client",
+		gen_proto.BidService_ListTenderBids_FullMethodName:        "client",
+		gen_proto.BidService_CreateBid_FullMethodName:             "contractor",
+		gen_proto.BidService_ListContractorBids_FullMethodName:    "contractor",
+		gen_proto.BidService_DeleteBid_FullMethodName:             "contractor",
+	},
+}
+
+// grpcRoutes are the REST routes the methods mirror, so the rate limit
+// policies of a route also apply to its method.
+var grpcRoutes = map[string]string{
+	gen_proto.UserService_Register_FullMethodName:             "POST /register",
+	gen_proto.UserService_Login_FullMethodName:                "POST /login",
+	gen_proto.UserService_GetUser_FullMethodName:              "GET /users/:user_id",
+	gen_proto.UserService_UpdateUser_FullMethodName:           "PUT /users",
+	gen_proto.UserService_DeleteUser_FullMethodName:           "DELETE /users",
+	gen_proto.UserService_StreamNotifications_FullMethodName:  "GET /notifications/stream",
+	gen_proto.TenderService_CreateTender_FullMethodName:       "POST /api/client/tenders",
+	gen_proto.TenderService_GetTender_FullMethodName:          "GET /api/client/tenders/:tender_id",
+	gen_proto.TenderService_ListTenders_FullMethodName:        "GET /api/client/tenders",
+	gen_proto.TenderService_UpdateTenderStatus_FullMethodName: "PUT /api/client/tenders/:tender_id",
+	gen_proto.TenderService_DeleteTender_FullMethodName:       "DELETE /api/client/tenders/:tender_id",
+	gen_proto.TenderService_AwardTender_FullMethodName:        "POST /api/client/tenders/:tender_id/award/:bid_id",
+	gen_proto.BidService_CreateBid_FullMethodName:             "POST /api/contractor/tenders/:tender_id/bid",
+	gen_proto.BidService_GetBid_FullMethodName:                "GET /api/contractor/tenders/:tender_id/bid/:bid_id",
+	gen_proto.BidService_ListTenderBids_FullMethodName:        "GET /api/client/tenders/:tender_id/bids",
+	gen_proto.BidService_ListContractorBids_FullMethodName:    "GET /api/contractor/bids",
+	gen_proto.BidService_DeleteBid_FullMethodName:             "DELETE /api/contractor/bids/:bid_id",
+}
+
+// NewGRPCServer serves the tender, bid and user services over gRPC with the
+// same services as the REST handlers h, and notification streams through ns.
+// Reflection is enabled so tools such as grpcurl can list the services.
+// Calls are rate limited by the REST policies through limiter.
+func NewGRPCServer(cfg *config.Config, h *handlers.HTTPHandler, ns *notification.Server, limiter *rate_limiter.Limiter) *grpc.Server {
+	rateLimit := interceptor.RateLimit{
+		Limiter:  limiter,
+		Policies: cfg.RateLimit.Policies,
+		Tokens:   h.Tokens,
+		APIKeys:  cfg.RateLimit.APIKeys,
+		Routes:   grpcRoutes,
+	}
+
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			interceptor.UnaryRequestID(),
+			interceptor.UnaryAccessLog(),
+			interceptor.UnaryErrors(),
+			interceptor.UnaryRecovery(),
+			interceptor.UnaryRateLimit(rateLimit),
+			interceptor.UnaryAuth(h.Tokens, grpcPolicy),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamRequestID(),
+			interceptor.StreamAccessLog(),
+			interceptor.StreamErrors(),
+			interceptor.StreamRecovery(),
+			interceptor.StreamRateLimit(rateLimit),
+			interceptor.StreamAuth(h.Tokens, grpcPolicy),
+		),
+	)
+
+	gen_proto.RegisterTenderServiceServer(srv, grpc_handlers.NewTenderServer(h.TenderService))
+	gen_proto.RegisterBidServiceServer(srv, grpc_handlers.NewBidServer(h.BidService))
+	gen_proto.RegisterUserServiceServer(srv, grpc_handlers.NewUserServer(h.UserService, h.Tokens, ns))
+	reflection.Register(srv)
+
+	return srv
+}
@@ -1,14 +1,18 @@
 package api
 
 import (
+	"tender-backend/config"
 	_ "tender-backend/docs"
 	"tender-backend/internal/http/handlers"
 	"tender-backend/internal/http/middleware"
-	"time"
+	"tender-backend/notification"
+	"tender-backend/rate_limiter"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	files "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // @tag.name Authentication
@@ -20,6 +24,21 @@ import (
 // @tag.name Bid
 // @tag.description Bid methods
 
+// @tag.name Notification
+// @tag.description Notification channels and preferences
+
+// @tag.name SavedSearch
+// @tag.description Saved tender searches and matching alerts for contractors
+
+// @tag.name Webhook
+// @tag.description Signed outgoing webhooks for tender and bid events
+
+// @tag.name Health
+// @tag.description Service health and dependency state
+
+// @tag.name Admin
+// @tag.description Operational endpoints, authenticated with the X-Admin-Key header
+
 // NewGinRouter godoc
 // @Title Tender API Gateway
 // @Version 1.0
@@ -27,22 +46,44 @@ import (
 // @SecurityDefinitions.apikey BearerAuth
 // @In header
 // @Name Authorization
-func NewGinRouter(h *handlers.HTTPHandler) *gin.Engine {
-	router := gin.Default()
+func NewGinRouter(cfg *config.Config, h *handlers.HTTPHandler, ns *notification.Server, limiter *rate_limiter.Limiter) (*gin.Engine, error) {
+	router := gin.New()
+	// Without trusted proxies ClientIP is the connection address, which a
+	// client cannot forge with X-Forwarded-For.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	router.Use(middleware.RequestIDMiddleware(), otelgin.Middleware(cfg.Tracing.ServiceName))
+	router.Use(middleware.AccessLogMiddleware(), middleware.MetricsMiddleware(), middleware.ErrorMiddleware(), middleware.RecoveryMiddleware())
+
+	// Probes and metrics are registered before the rate limiter, which does
+	// not apply to them, so scrapes and probes never get 429 or 503 from it.
+	router.GET("/healthz", h.Liveness)
+	router.GET("/readyz", h.Readiness)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	router.Use(middleware.RateLimitMiddleware(limiter, cfg.RateLimit.Policies, h.Tokens, cfg.RateLimit.APIKeys))
 
 	swaggerUrl := ginSwagger.URL("swagger/doc.json")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(files.Handler, swaggerUrl))
 
+	// Health, including the Redis circuit breaker
+	router.GET("/health", h.Health)
+
 	// Auth routes
 	router.POST("/login", h.Login)
 	router.POST("/register", h.Register)
 	router.GET("/users/:user_id", h.GetUserByID)
 
 	// User routes (protected)
-	userGroup := router.Group("/users").Use(middleware.JWTMiddleware())
+	userGroup := router.Group("/users").Use(middleware.JWTMiddleware(h.Tokens))
 	{
 		userGroup.PUT("", h.UpdateUser)
 		userGroup.DELETE("", h.DeleteUser)
+		userGroup.GET("/notification-preferences", h.GetNotificationPreferences)
+		userGroup.PUT("/notification-preferences", h.SetNotificationPreference)
+		userGroup.GET("/notification-settings", h.GetNotificationSettings)
+		userGroup.PUT("/notification-settings", h.UpdateNotificationSettings)
 	}
 
 	// Tender Routes
@@ -51,7 +92,7 @@ func NewGinRouter(h *handlers.HTTPHandler) *gin.Engine {
 		tenderGroup.GET("/:tender_id", h.GetTender)
 		tenderGroup.GET("", h.GetTenders)
 
-		protectedTenderGroup := tenderGroup.Use(middleware.JWTMiddleware(), middleware.ClientMiddleware())
+		protectedTenderGroup := tenderGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ClientMiddleware())
 		protectedTenderGroup.POST("", h.CreateTender)
 		protectedTenderGroup.PUT("/:tender_id", h.UpdateTender)
 		protectedTenderGroup.DELETE("/:tender_id", h.DeleteTender)
@@ -62,27 +103,63 @@ func NewGinRouter(h *handlers.HTTPHandler) *gin.Engine {
 
 	bidGroup.GET("/:bid_id", h.GetBid)
 
-	bidSubmissionRateLimit := middleware.RateLimitMiddleware(
-		5,           // Max 5 requests
-		time.Minute, // Per minute
-	)
-
 	clientBidsGroup := router.Group("/api/client/tenders/:tender_id/bids")
-	clientBidsGroup.Use(middleware.JWTMiddleware(), middleware.ClientMiddleware())
+	clientBidsGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ClientMiddleware())
 	clientBidsGroup.GET("", h.GetBids)
 
 	// Protected POST routes for bids
-	protectedBidGroup := bidGroup.Use(middleware.JWTMiddleware(), middleware.ContractorMiddleware())
-	protectedBidGroup.POST("", bidSubmissionRateLimit, h.CreateBid)
+	protectedBidGroup := bidGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ContractorMiddleware())
+	protectedBidGroup.POST("", h.CreateBid)
 
 	contractorBidGroup := router.Group("/api/contractor/bids")
-	contractorBidGroup.Use(middleware.JWTMiddleware(), middleware.ContractorMiddleware())
+	contractorBidGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ContractorMiddleware())
 	contractorBidGroup.GET("", h.GetContractorBids)
 	contractorBidGroup.DELETE("/:bid_id", h.DeleteBid)
 
+	// Watched tenders get deadline reminders without a bid
+	watchGroup := router.Group("/api/contractor/tenders/:tender_id/watch")
+	watchGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ContractorMiddleware())
+	watchGroup.POST("", h.WatchTender)
+	watchGroup.DELETE("", h.UnwatchTender)
+
+	watchedTendersGroup := router.Group("/api/contractor/watched-tenders")
+	watchedTendersGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ContractorMiddleware())
+	watchedTendersGroup.GET("", h.GetWatchedTenders)
+
+	savedSearchGroup := router.Group("/api/contractor/saved-searches")
+	savedSearchGroup.Use(middleware.JWTMiddleware(h.Tokens), middleware.ContractorMiddleware())
+	savedSearchGroup.POST("", h.CreateSavedSearch)
+	savedSearchGroup.GET("", h.GetSavedSearches)
+	savedSearchGroup.DELETE("/:search_id", h.DeleteSavedSearch)
+
 	// Awards routes
 	awardGroup := tenderGroup.Group("/:tender_id/award")
 	awardGroup.POST("/:bid_id", h.AwardTender)
 
-	return router
+	// Real-time notification routes, WebSocket with a Server-Sent Events fallback
+	notificationGroup := router.Group("/notifications")
+	notificationGroup.Use(middleware.JWTMiddleware(h.Tokens))
+	notificationGroup.GET("", h.GetNotifications)
+	notificationGroup.GET("/ws", ns.HandleConnection)
+	notificationGroup.GET("/stream", ns.HandleSSE)
+
+	// Webhook routes
+	webhookGroup := router.Group("/api/webhooks")
+	webhookGroup.Use(middleware.JWTMiddleware(h.Tokens))
+	webhookGroup.POST("", h.CreateWebhook)
+	webhookGroup.GET("", h.GetWebhooks)
+	webhookGroup.DELETE("/:webhook_id", h.DeleteWebhook)
+	webhookGroup.POST("/:webhook_id/enable", h.EnableWebhook)
+	webhookGroup.GET("/:webhook_id/deliveries", h.GetWebhookDeliveries)
+	webhookGroup.POST("/:webhook_id/deliveries/:delivery_id/replay", h.ReplayWebhookDelivery)
+
+	// Admin routes
+	adminGroup := router.Group("/api/admin")
+	adminGroup.Use(middleware.AdminMiddleware(cfg.AdminAPIKey))
+	adminGroup.GET("/rate-limits/policies", h.GetRateLimitPolicies)
+	adminGroup.GET("/rate-limits", h.GetRateLimitUsage)
+	adminGroup.DELETE("/rate-limits", h.ResetRateLimit)
+	adminGroup.PUT("/users/:user_id/organization", h.SetUserOrganization)
+
+	return router, nil
 }
@@ -0,0 +1,59 @@
+package broker
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrClosed = errors.New("broker is closed")
+
+// Message is a payload published to a topic.
+type Message struct {
+	Body    []byte
+	Headers map[string]string
+}
+
+// Delivery is a message received from a subscription. Every delivery must be
+// acknowledged, or negatively acknowledged to have it redelivered.
+type Delivery struct {
+	Message
+	ack  func() error
+	nack func(requeue bool) error
+	ctx  context.Context
+}
+
+// Context returns the context to process the delivery with, which carries
+// its consumer span on instrumented brokers.
+func (d *Delivery) Context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
+func (d *Delivery) Ack() error {
+	return d.ack()
+}
+
+func (d *Delivery) Nack(requeue bool) error {
+	return d.nack(requeue)
+}
+
+type SubscribeOptions struct {
+	// Ephemeral subscriptions belong to this process only and are removed
+	// when it stops, such as the per-instance notification queues.
+	Ephemeral bool
+}
+
+// Broker publishes messages to topics and delivers them to subscribers.
+// Subscribers of the same topic compete for messages, each message is
+// delivered to one of them.
+type Broker interface {
+	Publish(ctx context.Context, topic string, msg Message) error
+	// Subscribe delivers the messages of topic until ctx is done, then closes
+	// the channel. Deliveries received before may still be acknowledged.
+	Subscribe(ctx context.Context, topic string, opts SubscribeOptions) (<-chan *Delivery, error)
+	// Ping reports whether the broker is reachable, for readiness checks.
+	Ping(ctx context.Context) error
+	Close() error
+}
@@ -0,0 +1,112 @@
+package broker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"tender-backend/metrics"
+	"tender-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PublishedAtHeader holds when a message was first published, in Unix
+// milliseconds. Forwarded messages keep it, so consumer lag covers every hop.
+const PublishedAtHeader = "published_at"
+
+var tracer = tracing.Tracer("broker")
+
+type instrumented struct {
+	Broker
+}
+
+// Instrument wraps b to count published messages, measure how long messages
+// wait before they are consumed and trace publishing and consuming. The W3C
+// trace context travels in the message headers, so consumer spans continue
+// the trace of the request that published the message.
+func Instrument(b Broker) Broker {
+	return &instrumented{Broker: b}
+}
+
+func (b *instrumented) Publish(ctx context.Context, topic string, msg Message) error {
+	// Messages published outside a trace, such as by the outbox relay,
+	// continue the trace they were written in.
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = tracing.Extract(ctx, msg.Headers)
+	}
+
+	family := topicFamily(topic)
+	ctx, span := tracer.Start(ctx, family+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingDestinationName(topic), attribute.Int("messaging.message.body.size", len(msg.Body))))
+	defer span.End()
+
+	msg.Headers = tracing.Inject(ctx, msg.Headers)
+	if _, ok := msg.Headers[PublishedAtHeader]; !ok {
+		msg.Headers[PublishedAtHeader] = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	}
+
+	if err := b.Broker.Publish(ctx, topic, msg); err != nil {
+		metrics.MessagePublishFailures.WithLabelValues(family).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	metrics.MessagesPublished.WithLabelValues(family).Inc()
+	return nil
+}
+
+func (b *instrumented) Subscribe(ctx context.Context, topic string, opts SubscribeOptions) (<-chan *Delivery, error) {
+	deliveries, err := b.Broker.Subscribe(ctx, topic, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	family := topicFamily(topic)
+	lag := metrics.ConsumerLag.WithLabelValues(family)
+	out := make(chan *Delivery)
+	go func() {
+		defer close(out)
+		for delivery := range deliveries {
+			if publishedAt, err := strconv.ParseInt(delivery.Headers[PublishedAtHeader], 10, 64); err == nil {
+				lag.Observe(time.Since(time.UnixMilli(publishedAt)).Seconds())
+			}
+			out <- traceDelivery(ctx, family, topic, delivery)
+		}
+	}()
+	return out, nil
+}
+
+// traceDelivery starts the consumer span of a delivery, which ends once it
+// is acknowledged.
+func traceDelivery(ctx context.Context, family, topic string, delivery *Delivery) *Delivery {
+	ctx, span := tracer.Start(tracing.Extract(ctx, delivery.Headers), family+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.MessagingDestinationName(topic), attribute.Int("messaging.message.body.size", len(delivery.Body))))
+
+	return &Delivery{
+		Message: delivery.Message,
+		ack: func() error {
+			defer span.End()
+			return delivery.Ack()
+		},
+		nack: func(requeue bool) error {
+			defer span.End()
+			span.SetStatus(codes.Error, "message was not processed")
+			return delivery.Nack(requeue)
+		},
+		ctx: ctx,
+	}
+}
+
+// topicFamily groups the per-instance notification topics under one label.
+func topicFamily(topic string) string {
+	if strings.HasPrefix(topic, "notifications.instance.") {
+		return "notifications.instance"
+	}
+	return topic
+}
@@ -0,0 +1,109 @@
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+const memoryQueueSize = 1024
+
+// MemoryBroker keeps messages in process memory. It is meant for tests and
+// single-node development, messages are lost when the process stops.
+type MemoryBroker struct {
+	mu     sync.Mutex
+	queues map[string]chan Message
+	closed chan struct{}
+	once   sync.Once
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		queues: make(map[string]chan Message),
+		closed: make(chan struct{}),
+	}
+}
+
+func (b *MemoryBroker) queue(topic string) chan Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, ok := b.queues[topic]
+	if !ok {
+		q = make(chan Message, memoryQueueSize)
+		b.queues[topic] = q
+	}
+	return q
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, msg Message) error {
+	select {
+	case b.queue(topic) <- msg:
+		return nil
+	case <-b.closed:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string, opts SubscribeOptions) (<-chan *Delivery, error) {
+	q := b.queue(topic)
+
+	deliveries := make(chan *Delivery)
+	go func() {
+		defer close(deliveries)
+
+		for {
+			select {
+			case msg := <-q:
+				delivery := &Delivery{
+					Message: msg,
+					ack:     func() error { return nil },
+					nack: func(requeue bool) error {
+						if !requeue {
+							return nil
+						}
+						return b.Publish(context.Background(), topic, msg)
+					},
+				}
+
+				select {
+				case deliveries <- delivery:
+				case <-ctx.Done():
+					b.requeue(q, msg)
+					return
+				case <-b.closed:
+					return
+				}
+			case <-ctx.Done():
+				return
+			case <-b.closed:
+				return
+			}
+		}
+	}()
+
+	return deliveries, nil
+}
+
+// requeue hands back a message the stopped subscriber never delivered.
+func (b *MemoryBroker) requeue(q chan Message, msg Message) {
+	select {
+	case q <- msg:
+	default:
+	}
+}
+
+func (b *MemoryBroker) Ping(ctx context.Context) error {
+	select {
+	case <-b.closed:
+		return ErrClosed
+	default:
+		return nil
+	}
+}
+
+func (b *MemoryBroker) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
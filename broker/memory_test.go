@@ -0,0 +1,133 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryBrokerAcknowledgement(t *testing.T) {
+	tests := []struct {
+		name   string
+		settle func(d *Delivery) error
+		again  bool
+	}{
+		{"ack", func(d *Delivery) error { return d.Ack() }, false},
+		{"nack with requeue", func(d *Delivery) error { return d.Nack(true) }, true},
+		{"nack without requeue", func(d *Delivery) error { return d.Nack(false) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMemoryBroker()
+			defer b.Close()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			deliveries, err := b.Subscribe(ctx, "topic", SubscribeOptions{})
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			msg := Message{Body: []byte("a"), Headers: map[string]string{"event_type": "test"}}
+			if err := b.Publish(ctx, "topic", msg); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+
+			delivery := receive(t, deliveries)
+			if string(delivery.Body) != "a" || delivery.Headers["event_type"] != "test" {
+				t.Fatalf("got %q with headers %v", delivery.Body, delivery.Headers)
+			}
+			if err := tt.settle(delivery); err != nil {
+				t.Fatalf("settle: %v", err)
+			}
+
+			select {
+			case again := <-deliveries:
+				if !tt.again {
+					t.Errorf("delivered again: %q", again.Body)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.again {
+					t.Error("not delivered again")
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryBrokerCompetingSubscribers(t *testing.T) {
+	b := NewMemoryBroker()
+	defer b.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, _ := b.Subscribe(ctx, "topic", SubscribeOptions{})
+	second, _ := b.Subscribe(ctx, "topic", SubscribeOptions{})
+
+	const messages = 20
+	for i := 0; i < messages; i++ {
+		if err := b.Publish(ctx, "topic", Message{Body: []byte{byte(i)}}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	// Every message goes to exactly one of the subscribers
+	seen := make(map[byte]int)
+	for i := 0; i < messages; i++ {
+		var delivery *Delivery
+		select {
+		case delivery = <-first:
+		case delivery = <-second:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %d of %d messages", i, messages)
+		}
+		seen[delivery.Body[0]]++
+		_ = delivery.Ack()
+	}
+	for i := 0; i < messages; i++ {
+		if seen[byte(i)] != 1 {
+			t.Errorf("message %d delivered %d times", i, seen[byte(i)])
+		}
+	}
+}
+
+func TestMemoryBrokerStoppedSubscriber(t *testing.T) {
+	b := NewMemoryBroker()
+	defer b.Close()
+
+	// A subscriber that stops hands back the message it did not deliver
+	ctx, cancel := context.WithCancel(context.Background())
+	deliveries, _ := b.Subscribe(ctx, "topic", SubscribeOptions{})
+	if err := b.Publish(context.Background(), "topic", Message{Body: []byte("a")}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Give the subscriber time to take the message from the queue
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range deliveries {
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	deliveries, _ = b.Subscribe(ctx, "topic", SubscribeOptions{})
+	if delivery := receive(t, deliveries); string(delivery.Body) != "a" {
+		t.Errorf("got %q, want a", delivery.Body)
+	}
+}
+
+func TestMemoryBrokerClose(t *testing.T) {
+	b := NewMemoryBroker()
+	deliveries, _ := b.Subscribe(context.Background(), "topic", SubscribeOptions{})
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if _, ok := <-deliveries; ok {
+		t.Error("subscription still open after close")
+	}
+	if err := b.Ping(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("ping: got %v, want ErrClosed", err)
+	}
+
+}
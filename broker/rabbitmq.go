@@ -0,0 +1,149 @@
+package broker
+
+import (
+	"context"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// exchangeName is the direct exchange every topic is routed through, so
+// publishers never have to declare the queues of their subscribers.
+const exchangeName = "tender"
+
+type RabbitMQBroker struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+	mu   sync.Mutex // guards publishing on the shared channel
+}
+
+func NewRabbitMQBroker(url string) (*RabbitMQBroker, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := ch.ExchangeDeclare(exchangeName, "direct", true, false, false, false, nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &RabbitMQBroker{conn: conn, ch: ch}, nil
+}
+
+func (b *RabbitMQBroker) Publish(ctx context.Context, topic string, msg Message) error {
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ch.PublishWithContext(ctx,
+		exchangeName, topic, false, false,
+		amqp.Publishing{
+			ContentType:  "application/octet-stream",
+			DeliveryMode: amqp.Persistent,
+			Headers:      headers,
+			Body:         msg.Body,
+		},
+	)
+}
+
+func (b *RabbitMQBroker) Subscribe(ctx context.Context, topic string, opts SubscribeOptions) (<-chan *Delivery, error) {
+	// Each subscription gets its own channel so acknowledgements and
+	// prefetch do not interfere with publishing.
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	durable, autoDelete, exclusive := true, false, false
+	if opts.Ephemeral {
+		durable, autoDelete, exclusive = false, true, true
+	}
+
+	if _, err := ch.QueueDeclare(topic, durable, autoDelete, exclusive, false, nil); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	if err := ch.QueueBind(topic, topic, exchangeName, false, nil); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	if err := ch.Qos(32, 0, false); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	messages, err := ch.ConsumeWithContext(ctx, topic, "", false, exclusive, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	deliveries := make(chan *Delivery)
+	go func() {
+		// Acknowledgements go over the channel, so it is closed only once the
+		// deliveries handed out before the subscription stopped were settled.
+		var inFlight sync.WaitGroup
+		defer func() {
+			close(deliveries)
+			inFlight.Wait()
+			ch.Close()
+		}()
+
+		for msg := range messages {
+			headers := make(map[string]string, len(msg.Headers))
+			for key, value := range msg.Headers {
+				if s, ok := value.(string); ok {
+					headers[key] = s
+				}
+			}
+
+			inFlight.Add(1)
+			settled := sync.OnceFunc(inFlight.Done)
+			delivery := &Delivery{
+				Message: Message{Body: msg.Body, Headers: headers},
+				ack: func() error {
+					defer settled()
+					return msg.Ack(false)
+				},
+				nack: func(requeue bool) error {
+					defer settled()
+					return msg.Nack(false, requeue)
+				},
+			}
+
+			select {
+			case deliveries <- delivery:
+			case <-ctx.Done():
+				// Never handed out, the broker redelivers it once the channel is closed.
+				settled()
+				return
+			}
+		}
+	}()
+
+	return deliveries, nil
+}
+
+func (b *RabbitMQBroker) Ping(ctx context.Context) error {
+	if b.conn.IsClosed() {
+		return amqp.ErrClosed
+	}
+	return nil
+}
+
+func (b *RabbitMQBroker) Close() error {
+	return b.conn.Close()
+}
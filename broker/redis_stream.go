@@ -0,0 +1,152 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	streamMaxLen     = 100000
+	streamBlock      = 5 * time.Second
+	streamBatchSize  = 32
+	streamHeaderPref = "h:"
+)
+
+// RedisStreamBroker maps every topic to a Redis stream consumed through a
+// consumer group, so subscribers on different instances share the work.
+type RedisStreamBroker struct {
+	redis    *redis.Client
+	group    string
+	consumer string
+}
+
+// NewRedisStreamBroker creates a broker on the given client. Subscribers with
+// the same group compete for messages; consumer names this instance.
+func NewRedisStreamBroker(redisClient *redis.Client, group, consumer string) *RedisStreamBroker {
+	return &RedisStreamBroker{
+		redis:    redisClient,
+		group:    group,
+		consumer: consumer,
+	}
+}
+
+func streamKey(topic string) string {
+	return "stream:" + topic
+}
+
+func (b *RedisStreamBroker) Publish(ctx context.Context, topic string, msg Message) error {
+	values := map[string]interface{}{"body": msg.Body}
+	for key, value := range msg.Headers {
+		values[streamHeaderPref+key] = value
+	}
+
+	return b.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamKey(topic),
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+func (b *RedisStreamBroker) Subscribe(ctx context.Context, topic string, opts SubscribeOptions) (<-chan *Delivery, error) {
+	stream := streamKey(topic)
+
+	if err := b.redis.XGroupCreateMkStream(ctx, stream, b.group, "$").Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, err
+	}
+
+	deliveries := make(chan *Delivery)
+	go func() {
+		defer close(deliveries)
+		if opts.Ephemeral {
+			defer b.redis.Del(context.Background(), stream)
+		}
+
+		// Start with the messages this consumer received but never
+		// acknowledged, reading past each batch so none is handed out twice,
+		// then continue with new messages.
+		start := "0"
+		for ctx.Err() == nil {
+			streams, err := b.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    b.group,
+				Consumer: b.consumer,
+				Streams:  []string{stream, start},
+				Count:    streamBatchSize,
+				Block:    streamBlock,
+			}).Result()
+			if err != nil {
+				if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+					continue
+				}
+				slog.ErrorContext(ctx, "Failed to read stream", "stream", stream, "error", err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			lastID := ""
+			for _, s := range streams {
+				for _, msg := range s.Messages {
+					lastID = msg.ID
+					select {
+					case deliveries <- b.toDelivery(ctx, stream, topic, msg):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			if start != ">" {
+				if lastID == "" {
+					start = ">"
+				} else {
+					start = lastID
+				}
+			}
+		}
+	}()
+
+	return deliveries, nil
+}
+
+func (b *RedisStreamBroker) toDelivery(ctx context.Context, stream, topic string, msg redis.XMessage) *Delivery {
+	delivery := &Delivery{Message: Message{Headers: map[string]string{}}}
+	for key, value := range msg.Values {
+		s, _ := value.(string)
+		if key == "body" {
+			delivery.Body = []byte(s)
+		} else if strings.HasPrefix(key, streamHeaderPref) {
+			delivery.Headers[strings.TrimPrefix(key, streamHeaderPref)] = s
+		}
+	}
+
+	delivery.ack = func() error {
+		return b.redis.XAck(context.Background(), stream, b.group, msg.ID).Err()
+	}
+	delivery.nack = func(requeue bool) error {
+		// The copy is published before the original is acknowledged, so a
+		// failure in between duplicates the message instead of losing it.
+		if requeue {
+			// ctx may be done already when a stopping consumer gives the message back.
+			if err := b.Publish(context.WithoutCancel(ctx), topic, delivery.Message); err != nil {
+				return err
+			}
+		}
+		return b.redis.XAck(context.Background(), stream, b.group, msg.ID).Err()
+	}
+
+	return delivery
+}
+
+func (b *RedisStreamBroker) Ping(ctx context.Context) error {
+	return b.redis.Ping(ctx).Err()
+}
+
+// Close is a no-op, the Redis client is owned by the caller.
+func (b *RedisStreamBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,104 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func receive(t *testing.T, deliveries <-chan *Delivery) *Delivery {
+	t.Helper()
+
+	select {
+	case delivery := <-deliveries:
+		return delivery
+	case <-time.After(2 * time.Second):
+		t.Fatal("no delivery")
+		return nil
+	}
+}
+
+func TestRedisStreamPendingOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	b := NewRedisStreamBroker(client, "group", "consumer")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deliveries, err := b.Subscribe(ctx, "topic", SubscribeOptions{})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	for _, body := range []string{"a", "b"} {
+		if err := b.Publish(ctx, "topic", Message{Body: []byte(body)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	// The consumer stops before acknowledging either message
+	receive(t, deliveries)
+	receive(t, deliveries)
+	cancel()
+	for range deliveries {
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	deliveries, err = b.Subscribe(ctx, "topic", SubscribeOptions{})
+	if err != nil {
+		t.Fatalf("subscribe again: %v", err)
+	}
+
+	// The pending messages come back once each, even while unacknowledged,
+	// and are followed by new messages
+	var bodies []string
+	for i := 0; i < 2; i++ {
+		bodies = append(bodies, string(receive(t, deliveries).Body))
+	}
+	if err := b.Publish(ctx, "topic", Message{Body: []byte("c")}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	bodies = append(bodies, string(receive(t, deliveries).Body))
+
+	if len(bodies) != 3 || bodies[0] != "a" || bodies[1] != "b" || bodies[2] != "c" {
+		t.Errorf("got %v, want [a b c]", bodies)
+	}
+}
+
+func TestRedisStreamNackRequeues(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	b := NewRedisStreamBroker(client, "group", "consumer")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deliveries, err := b.Subscribe(ctx, "topic", SubscribeOptions{})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := b.Publish(ctx, "topic", Message{Body: []byte("a"), Headers: map[string]string{"event_type": "test"}}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if err := receive(t, deliveries).Nack(true); err != nil {
+		t.Fatalf("nack: %v", err)
+	}
+	requeued := receive(t, deliveries)
+	if string(requeued.Body) != "a" || requeued.Headers["event_type"] != "test" {
+		t.Errorf("requeued %q with headers %v", requeued.Body, requeued.Headers)
+	}
+	if err := requeued.Ack(); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+
+	pending, err := client.XPending(ctx, streamKey("topic"), "group").Result()
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if pending.Count != 0 {
+		t.Errorf("%d messages pending, want 0", pending.Count)
+	}
+}
@@ -0,0 +1,379 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"tender-backend/circuit_breaker"
+	"tender-backend/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache is a read-through cache on Redis. Entries are invalidated by tag:
+// every tag has a version counter, an entry remembers the versions of its tags
+// from before it was loaded, and invalidating a tag bumps its version. Entries
+// written by a load that raced with a write are therefore never served, which
+// deleting keys alone cannot guarantee.
+//
+// Tag versions expire after TagTTL without invalidations. A version can only
+// return to a value an entry remembers once the tag expired, so entry TTLs
+// must be shorter than TagTTL.
+//
+// While Redis is unavailable, entries are served from the optional local
+// fallback, and invalidations that could not reach Redis are retried before
+// this process reads Redis again, so it never serves what it changed during
+// the outage. The pending invalidations are kept in this process only: other
+// replicas may read Redis first and serve those entries until it retried them,
+// or until the entries expire if it stopped in between.
+type Cache struct {
+	client    *redis.Client
+	namespace string
+	local     *Local
+	group     singleflight.Group
+
+	// pendingMu also serializes retries of the pending invalidations.
+	pendingMu sync.Mutex
+	pending   map[string]bool
+	fallbacks atomic.Int64
+}
+
+// New creates a cache whose keys all start with "cache:<namespace>:". local
+// may be nil, in which case every read loads from the source while Redis is down.
+// client may be nil too, e.g. in tests, in which case nothing is cached.
+func New(client *redis.Client, namespace string, local *Local) *Cache {
+	return &Cache{
+		client:    client,
+		namespace: namespace,
+		local:     local,
+		pending:   make(map[string]bool),
+	}
+}
+
+// Stats describes the cache for health checks and metrics.
+type Stats struct {
+	LocalEntries         int   `json:"local_entries"`
+	PendingInvalidations int   `json:"pending_invalidations"`
+	Fallbacks            int64 `json:"fallbacks"`
+}
+
+func (c *Cache) Stats() Stats {
+	c.pendingMu.Lock()
+	pending := len(c.pending)
+	c.pendingMu.Unlock()
+
+	return Stats{
+		LocalEntries:         c.local.Len(),
+		PendingInvalidations: pending,
+		Fallbacks:            c.fallbacks.Load(),
+	}
+}
+
+// TagTTL is how long a tag version is kept without invalidations.
+const TagTTL = 24 * time.Hour
+
+type entry struct {
+	Versions []int64         `json:"v"`
+	Data     json.RawMessage `json:"d"`
+}
+
+// Store is a typed group of cache entries, e.g. tenders by ID.
+type Store[T any] struct {
+	cache *Cache
+	name  string
+	ttl   time.Duration
+}
+
+// NewStore creates a store whose entries expire after ttl, which must be shorter than a day.
+func NewStore[T any](cache *Cache, name string, ttl time.Duration) *Store[T] {
+	return &Store[T]{
+		cache: cache,
+		name:  name,
+		ttl:   ttl,
+	}
+}
+
+// Get returns the entry for key, calling load on a miss. Concurrent misses of
+// the same key in this process share one load. tags must be the same for every
+// call with the key. When Redis fails, entries are read from and stored in the
+// local fallback instead; load errors are returned and never cached.
+func (s *Store[T]) Get(ctx context.Context, key string, tags []string, load func() (T, error)) (T, error) {
+	if s.cache.client == nil {
+		s.count("miss")
+		return load()
+	}
+
+	redisKey := s.cache.key(s.name + ":" + key)
+
+	available := s.cache.retryPending(ctx)
+	if available {
+		value, ok, err := s.read(ctx, redisKey, tags)
+		if ok {
+			s.count("hit")
+			return value, nil
+		}
+		if err != nil {
+			logRedisError(ctx, "Failed to read cache entry", err, "key", redisKey)
+			available = false
+		}
+	}
+
+	if !available {
+		s.cache.fallbacks.Add(1)
+		metrics.CacheFallbacks.Inc()
+		if value, ok := s.readLocal(redisKey); ok {
+			s.count("hit")
+			return value, nil
+		}
+	}
+
+	s.count("miss")
+
+	result, err, _ := s.cache.group.Do(redisKey, func() (interface{}, error) {
+		// Read the versions before loading, so a write that commits during the
+		// load invalidates what is stored below.
+		var versions []int64
+		if available {
+			var err error
+			versions, err = s.cache.versions(ctx, tags)
+			if err != nil {
+				logRedisError(ctx, "Failed to read cache tag versions", err)
+			}
+		}
+		generation := s.cache.local.currentGeneration()
+
+		value, err := load()
+		if err != nil {
+			return value, err
+		}
+
+		if versions != nil {
+			s.write(ctx, redisKey, versions, value)
+		} else {
+			s.writeLocal(redisKey, tags, value, generation)
+		}
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result.(T), nil
+}
+
+// count records a lookup in the cache metrics, by the store name as key family.
+func (s *Store[T]) count(result string) {
+	metrics.CacheLookups.WithLabelValues(s.name, result).Inc()
+}
+
+// read returns the entry from Redis. A miss returns no error.
+func (s *Store[T]) read(ctx context.Context, redisKey string, tags []string) (T, bool, error) {
+	var value T
+
+	pipe := s.cache.client.Pipeline()
+	get := pipe.Get(ctx, redisKey)
+	var versions *redis.SliceCmd
+	if len(tags) > 0 {
+		versions = pipe.MGet(ctx, s.cache.tagKeys(tags)...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return value, false, nil
+		}
+		return value, false, err
+	}
+
+	var cached entry
+	if err := json.Unmarshal([]byte(get.Val()), &cached); err != nil {
+		return value, false, nil
+	}
+
+	if versions != nil && !sameVersions(cached.Versions, versions.Val()) {
+		return value, false, nil
+	}
+
+	if err := json.Unmarshal(cached.Data, &value); err != nil {
+		return value, false, nil
+	}
+	return value, true, nil
+}
+
+func (s *Store[T]) readLocal(redisKey string) (T, bool) {
+	var value T
+
+	data, ok := s.cache.local.get(redisKey)
+	if !ok {
+		return value, false
+	}
+
+	// Entries are kept encoded, so callers never share a value.
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
+
+func (s *Store[T]) writeLocal(redisKey string, tags []string, value T, generation uint64) {
+	if s.cache.local == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Error("Failed to encode cache entry", "key", redisKey, "error", err)
+		return
+	}
+
+	s.cache.local.set(redisKey, tags, data, s.ttl, generation)
+}
+
+func (s *Store[T]) write(ctx context.Context, redisKey string, versions []int64, value T) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Error("Failed to encode cache entry", "key", redisKey, "error", err)
+		return
+	}
+
+	body, err := json.Marshal(entry{Versions: versions, Data: data})
+	if err != nil {
+		return
+	}
+
+	if err := s.cache.client.Set(ctx, redisKey, body, s.ttl).Err(); err != nil {
+		logRedisError(ctx, "Failed to write cache entry", err, "key", redisKey)
+	}
+}
+
+// Delete removes the entry for key.
+func (s *Store[T]) Delete(ctx context.Context, key string) error {
+	redisKey := s.cache.key(s.name + ":" + key)
+	s.cache.local.delete(redisKey)
+	if s.cache.client == nil {
+		return nil
+	}
+	return s.cache.client.Del(ctx, redisKey).Err()
+}
+
+// Invalidate makes every entry tagged with any of tags stale. Tags that could
+// not be invalidated in Redis are retried before Redis is read again.
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	c.local.invalidate(tags)
+	if c.client == nil {
+		return nil
+	}
+
+	if err := c.invalidate(ctx, tags); err != nil {
+		c.pendingMu.Lock()
+		for _, tag := range tags {
+			c.pending[tag] = true
+		}
+		metrics.CachePendingInvalidations.Set(float64(len(c.pending)))
+		c.pendingMu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// retryPending invalidates the tags whose invalidation failed and reports
+// whether Redis may be read, which is only once none are left.
+func (c *Cache) retryPending(ctx context.Context) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	if len(c.pending) == 0 {
+		return true
+	}
+
+	tags := make([]string, 0, len(c.pending))
+	for tag := range c.pending {
+		tags = append(tags, tag)
+	}
+
+	if err := c.invalidate(ctx, tags); err != nil {
+		return false
+	}
+
+	slog.InfoContext(ctx, "Invalidated cache tags changed while Redis was unavailable", "tags", len(tags))
+	c.pending = make(map[string]bool)
+	metrics.CachePendingInvalidations.Set(0)
+	return true
+}
+
+func (c *Cache) invalidate(ctx context.Context, tags []string) error {
+	pipe := c.client.Pipeline()
+	for _, tagKey := range c.tagKeys(tags) {
+		pipe.Incr(ctx, tagKey)
+		pipe.Expire(ctx, tagKey, TagTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// versions returns the current version of each tag, 0 for unknown tags.
+func (c *Cache) versions(ctx context.Context, tags []string) ([]int64, error) {
+	versions := make([]int64, len(tags))
+	if len(tags) == 0 {
+		return versions, nil
+	}
+
+	values, err := c.client.MGet(ctx, c.tagKeys(tags)...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		versions[i] = parseVersion(value)
+	}
+	return versions, nil
+}
+
+// logRedisError logs a failed Redis call, except those rejected by an open
+// circuit breaker, which would otherwise log on every request during an outage.
+func logRedisError(ctx context.Context, message string, err error, attrs ...any) {
+	if !errors.Is(err, circuit_breaker.ErrOpen) {
+		slog.WarnContext(ctx, message, append(attrs, "error", err)...)
+	}
+}
+
+func (c *Cache) key(name string) string {
+	return "cache:" + c.namespace + ":" + name
+}
+
+func (c *Cache) tagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = c.key("tag:" + tag)
+	}
+	return keys
+}
+
+func sameVersions(cached []int64, current []interface{}) bool {
+	if len(cached) != len(current) {
+		return false
+	}
+
+	for i, value := range current {
+		if cached[i] != parseVersion(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVersion reads a tag version from MGET, where missing tags are nil.
+func parseVersion(value interface{}) int64 {
+	s, _ := value.(string)
+	version, _ := strconv.ParseInt(s, 10, 64)
+	return version
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCache(t *testing.T, local *Local) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "test", local), mr
+}
+
+// loader returns successive versions of a value and counts the loads.
+type loader struct {
+	loads int
+}
+
+func (l *loader) load() (int, error) {
+	l.loads++
+	return l.loads, nil
+}
+
+func TestInvalidate(t *testing.T) {
+	tests := []struct {
+		name string
+		// between runs after the entry was first cached
+		between  func(ctx context.Context, c *Cache, store *Store[int]) error
+		wantLoad bool
+	}{
+		{
+			name:     "no change",
+			between:  func(context.Context, *Cache, *Store[int]) error { return nil },
+			wantLoad: false,
+		},
+		{
+			name: "other tag invalidated",
+			between: func(ctx context.Context, c *Cache, _ *Store[int]) error {
+				return c.Invalidate(ctx, "tender:2")
+			},
+			wantLoad: false,
+		},
+		{
+			name: "tag invalidated",
+			between: func(ctx context.Context, c *Cache, _ *Store[int]) error {
+				return c.Invalidate(ctx, "tenders", "tender:1")
+			},
+			wantLoad: true,
+		},
+		{
+			name: "entry deleted",
+			between: func(ctx context.Context, _ *Cache, store *Store[int]) error {
+				return store.Delete(ctx, "1")
+			},
+			wantLoad: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCache(t, nil)
+			store := NewStore[int](c, "tenders", time.Minute)
+			ctx := context.Background()
+			tags := []string{"tender:1"}
+			source := &loader{}
+
+			if value, err := store.Get(ctx, "1", tags, source.load); err != nil || value != 1 {
+				t.Fatalf("first get = %d, %v, want 1", value, err)
+			}
+			if err := tt.between(ctx, c, store); err != nil {
+				t.Fatalf("between: %v", err)
+			}
+
+			value, err := store.Get(ctx, "1", tags, source.load)
+			if err != nil {
+				t.Fatalf("second get: %v", err)
+			}
+			if loaded := value == 2; loaded != tt.wantLoad {
+				t.Errorf("second get = %d, loaded %v, want %v", value, loaded, tt.wantLoad)
+			}
+		})
+	}
+}
+
+func TestInvalidateDuringLoad(t *testing.T) {
+	c, _ := newTestCache(t, nil)
+	store := NewStore[string](c, "tenders", time.Minute)
+	ctx := context.Background()
+	tags := []string{"tender:1"}
+
+	// A write commits and invalidates while the old row is being loaded, so
+	// the entry stored by that load must not be served
+	value, err := store.Get(ctx, "1", tags, func() (string, error) {
+		if err := c.Invalidate(ctx, "tender:1"); err != nil {
+			t.Fatalf("invalidate: %v", err)
+		}
+		return "old", nil
+	})
+	if err != nil || value != "old" {
+		t.Fatalf("first get = %q, %v, want old", value, err)
+	}
+
+	value, err = store.Get(ctx, "1", tags, func() (string, error) { return "new", nil })
+	if err != nil || value != "new" {
+		t.Errorf("second get = %q, %v, want new", value, err)
+	}
+}
+
+func TestPendingInvalidations(t *testing.T) {
+	c, mr := newTestCache(t, NewLocal(10, time.Minute))
+	store := NewStore[int](c, "tenders", time.Minute)
+	ctx := context.Background()
+	tags := []string{"tender:1"}
+	source := &loader{}
+
+	if _, err := store.Get(ctx, "1", tags, source.load); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if err := c.Invalidate(ctx, "tender:1"); err == nil {
+		t.Fatal("invalidate succeeded while Redis was down")
+	}
+	if stats := c.Stats(); stats.PendingInvalidations != 1 {
+		t.Fatalf("pending invalidations = %d, want 1", stats.PendingInvalidations)
+	}
+
+	// The outage is served from the source and then from the local fallback
+	for _, want := range []int{2, 2} {
+		if value, err := store.Get(ctx, "1", tags, source.load); err != nil || value != want {
+			t.Errorf("get during outage = %d, %v, want %d", value, err, want)
+		}
+	}
+	if stats := c.Stats(); stats.Fallbacks != 2 {
+		t.Errorf("fallbacks = %d, want 2", stats.Fallbacks)
+	}
+
+	// Redis still holds the entry from before the invalidation, which must
+	// not be served once it is back
+	mr.SetError("")
+	if value, err := store.Get(ctx, "1", tags, source.load); err != nil || value != 3 {
+		t.Errorf("get after recovery = %d, %v, want 3", value, err)
+	}
+	if stats := c.Stats(); stats.PendingInvalidations != 0 {
+		t.Errorf("pending invalidations after recovery = %d, want 0", stats.PendingInvalidations)
+	}
+	if value, err := store.Get(ctx, "1", tags, source.load); err != nil || value != 3 {
+		t.Errorf("cached get after recovery = %d, %v, want 3", value, err)
+	}
+}
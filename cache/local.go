@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Local is a bounded in-process LRU that serves entries while Redis is
+// unavailable. It is only as fresh as this replica knows: invalidations made
+// by other replicas reach it through its TTL, which should therefore be short.
+type Local struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	// generation counts invalidations, so a load that raced with one is not stored.
+	generation uint64
+}
+
+type localEntry struct {
+	key       string
+	tags      []string
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewLocal creates a fallback of at most size entries that live for ttl.
+// It returns nil, which disables the fallback, when size is not positive.
+func NewLocal(size int, ttl time.Duration) *Local {
+	if size <= 0 {
+		return nil
+	}
+
+	return &Local{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (l *Local) get(key string) ([]byte, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := element.Value.(*localEntry)
+	if time.Now().After(e.expiresAt) {
+		l.remove(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	return e.data, true
+}
+
+// currentGeneration is read before a load and passed to set with its result.
+func (l *Local) currentGeneration() uint64 {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.generation
+}
+
+// set stores an entry unless an invalidation happened since generation.
+func (l *Local) set(key string, tags []string, data []byte, ttl time.Duration, generation uint64) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if generation != l.generation {
+		return
+	}
+
+	if ttl <= 0 || ttl > l.ttl {
+		ttl = l.ttl
+	}
+	e := &localEntry{key: key, tags: tags, data: data, expiresAt: time.Now().Add(ttl)}
+
+	if element, ok := l.entries[key]; ok {
+		element.Value = e
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(e)
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *Local) delete(key string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+}
+
+// invalidate drops every entry tagged with any of tags.
+func (l *Local) invalidate(tags []string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+
+	stale := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		stale[tag] = true
+	}
+
+	for element := l.order.Front(); element != nil; {
+		next := element.Next()
+		for _, tag := range element.Value.(*localEntry).tags {
+			if stale[tag] {
+				l.remove(element)
+				break
+			}
+		}
+		element = next
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (l *Local) Len() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *Local) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*localEntry).key)
+}
@@ -0,0 +1,179 @@
+package circuit_breaker
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"tender-backend/metrics"
+	"time"
+)
+
+// ErrOpen is returned instead of calling a dependency whose breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+	// Open rejects every call until the cooldown has passed.
+	Open
+	// HalfOpen lets a single probe through, which closes the breaker if it
+	// succeeds and opens it again if it fails.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats is a snapshot of a breaker for health checks and metrics.
+type Stats struct {
+	Name                string    `json:"name"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Failures            int64     `json:"failures"`
+	Rejected            int64     `json:"rejected"`
+	Opened              int64     `json:"opened"`
+	ChangedAt           time.Time `json:"changed_at"`
+}
+
+// Breaker stops calling a failing dependency. It opens after threshold
+// consecutive failures, rejects calls with ErrOpen for cooldown, and then lets
+// one probe through to find out whether the dependency recovered.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	probing   bool
+	changedAt time.Time
+
+	totalFailures int64
+	rejected      int64
+	opened        int64
+}
+
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	metrics.CircuitBreakerState.WithLabelValues(name).Set(float64(Closed))
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		changedAt: time.Now(),
+	}
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by Success, Failure or Release.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.changedAt) < b.cooldown {
+			b.reject()
+			return ErrOpen
+		}
+		b.setState(HalfOpen)
+		b.probing = true
+		return nil
+	case HalfOpen:
+		if b.probing {
+			b.reject()
+			return ErrOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a call that reached the dependency.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != Closed {
+		b.setState(Closed)
+	}
+}
+
+// Failure records a call that failed because of the dependency.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.totalFailures++
+	b.probing = false
+
+	if b.state == HalfOpen || (b.state == Closed && b.failures >= b.threshold) {
+		b.opened++
+		metrics.CircuitBreakerOpened.WithLabelValues(b.name).Inc()
+		b.setState(Open)
+	}
+}
+
+// Release ends a call that says nothing about the dependency, such as one
+// canceled by its caller, so that another probe may run.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return Stats{
+		Name:                b.name,
+		State:               b.state.String(),
+		ConsecutiveFailures: b.failures,
+		Failures:            b.totalFailures,
+		Rejected:            b.rejected,
+		Opened:              b.opened,
+		ChangedAt:           b.changedAt,
+	}
+}
+
+func (b *Breaker) reject() {
+	b.rejected++
+	metrics.CircuitBreakerRejections.WithLabelValues(b.name).Inc()
+}
+
+func (b *Breaker) setState(state State) {
+	slog.Warn("Circuit breaker changed state", "breaker", b.name, "from", b.state.String(), "to", state.String())
+	b.state = state
+	b.changedAt = time.Now()
+	metrics.CircuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+}
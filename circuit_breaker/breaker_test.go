@@ -0,0 +1,102 @@
+package circuit_breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const testCooldown = 20 * time.Millisecond
+
+func TestBreaker(t *testing.T) {
+	// Calls are made in order on one breaker with a threshold of 2. "wait"
+	// sleeps past the cooldown.
+	tests := []struct {
+		name      string
+		calls     []string
+		wantState State
+		wantErr   error
+	}{
+		{
+			name:      "closed below threshold",
+			calls:     []string{"failure", "success", "failure"},
+			wantState: Closed,
+		},
+		{
+			name:      "opens at threshold",
+			calls:     []string{"failure", "failure"},
+			wantState: Open,
+			wantErr:   ErrOpen,
+		},
+		{
+			name:      "half open after cooldown",
+			calls:     []string{"failure", "failure", "wait"},
+			wantState: Open,
+		},
+		{
+			name:      "single probe",
+			calls:     []string{"failure", "failure", "wait", "probe"},
+			wantState: HalfOpen,
+			wantErr:   ErrOpen,
+		},
+		{
+			name:      "probe succeeds",
+			calls:     []string{"failure", "failure", "wait", "probe", "success"},
+			wantState: Closed,
+		},
+		{
+			name:      "probe fails",
+			calls:     []string{"failure", "failure", "wait", "probe", "failure"},
+			wantState: Open,
+			wantErr:   ErrOpen,
+		},
+		{
+			name:      "probe released",
+			calls:     []string{"failure", "failure", "wait", "probe", "release"},
+			wantState: HalfOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New("test", 2, testCooldown)
+
+			for _, call := range tt.calls {
+				switch call {
+				case "wait":
+					time.Sleep(testCooldown + 5*time.Millisecond)
+				case "probe":
+					if err := b.Allow(); err != nil {
+						t.Fatalf("probe rejected: %v", err)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "release":
+					b.Release()
+				}
+			}
+
+			if state := b.State(); state != tt.wantState {
+				t.Errorf("state = %s, want %s", state, tt.wantState)
+			}
+			if err := b.Allow(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("allow = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBreakerStats(t *testing.T) {
+	b := New("test", 1, time.Hour)
+
+	b.Failure()
+	b.Allow()
+	b.Allow()
+
+	stats := b.Stats()
+	if stats.State != "open" || stats.Failures != 1 || stats.Opened != 1 || stats.Rejected != 2 {
+		t.Errorf("stats = %+v, want open with 1 failure, 1 opening and 2 rejections", stats)
+	}
+}
@@ -0,0 +1,70 @@
+package circuit_breaker
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisHook runs every command and pipeline of a Redis client through a
+// breaker, so the cache, rate limiter and presence tracker fail fast with
+// ErrOpen while Redis is down instead of each waiting for its own timeout.
+type redisHook struct {
+	breaker *Breaker
+}
+
+// NewRedisHook returns a hook for redis.Client.AddHook. The client still
+// reconnects on its own: the pool dials on demand, so the first probe after
+// the cooldown opens a new connection.
+func NewRedisHook(breaker *Breaker) redis.Hook {
+	return redisHook{breaker: breaker}
+}
+
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+
+		err := next(ctx, cmd)
+		h.record(err)
+		return err
+	}
+}
+
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+
+		err := next(ctx, cmds)
+		h.record(err)
+		return err
+	}
+}
+
+func (h redisHook) record(err error) {
+	var replyErr redis.Error
+	switch {
+	case err == nil, errors.As(err, &replyErr):
+		// Redis answered, even if the answer is redis.Nil or an error reply.
+		h.breaker.Success()
+	case errors.Is(err, context.Canceled):
+		h.breaker.Release()
+	default:
+		h.breaker.Failure()
+	}
+}
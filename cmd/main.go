@@ -2,52 +2,232 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"tender-backend/api"
+	"tender-backend/broker"
+	"tender-backend/circuit_breaker"
 	"tender-backend/config"
 	"tender-backend/db"
 	"tender-backend/internal/http/handlers"
+	"tender-backend/logging"
+	"tender-backend/notification"
+	"tender-backend/notification_channel"
+	"tender-backend/outbox"
+	"tender-backend/rate_limiter"
+	"tender-backend/repository"
+	"tender-backend/server"
+	"tender-backend/tracing"
+	"time"
+	_ "time/tzdata" // Quiet hours are evaluated in the time zones of users
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9" // Correct Redis import for v9
 )
 
-var redisClient *redis.Client
+var (
+	redisClient  *redis.Client
+	redisBreaker *circuit_breaker.Breaker
+)
 
 func main() {
+	// Schema migrations run as a subcommand, never on startup
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// A failed server or worker shuts the service down and exits with an
+	// error status once the deferred cleanup below has run.
+	var failure error
+	defer func() {
+		if failure != nil {
+			os.Exit(1)
+		}
+	}()
+
 	// Load configuration
-	config.LoadConfig()
+	cfg := loadConfig()
+
+	// Structured logging, which the log package writes through as well
+	if err := logging.Setup(os.Stderr, cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+
+	// Trace requests through PostgreSQL, Redis and the broker
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database
-	db.ConnectDB()
+	db.ConnectDB(cfg.DB, cfg.Log.SlowQueryThreshold)
 	defer db.CloseDB()
+	CheckSchema()
 
 	// Initialize Redis
-	InitRedis()
+	InitRedis(cfg.Redis)
 	defer redisClient.Close()
 
+	// Initialize the message broker
+	messageBroker := broker.Instrument(NewBroker(cfg))
+	defer messageBroker.Close()
+
+	// Stop on SIGINT and SIGTERM, which every deploy sends
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Consumers and periodic jobs run until the HTTP server drained on shutdown
+	workers := newWorkers()
+
+	// Repositories on PostgreSQL shared by the services
+	store := repository.NewGorm(db.DB)
+
+	// Deliver notifications to connected WebSocket and SSE clients
+	notificationService := server.NewNotificationService(store, redisClient, messageBroker, cfg.InstanceID, cfg.Notification, notification_channel.DefaultChannels(cfg.SMTP)...)
+	workers.GoErr(notificationService.ConsumeNotifications)
+	workers.GoErr(notificationService.ConsumeInstanceNotifications)
+	workers.Go(notificationService.RunPresenceHeartbeat)
+	notificationServer := notification.NewNotificationServer(notificationService)
+	go notificationServer.Run()
+
+	// Publish events recorded in the outbox to the broker
+	relay := outbox.NewRelay(db.DB, messageBroker)
+	workers.Go(func(ctx context.Context) { relay.Run(ctx, time.Second) })
+
+	// Send notifications that were not delivered over WebSocket to offline channels
+	workers.Go(func(ctx context.Context) { notificationService.RunOfflineFallback(ctx, time.Minute) })
+
+	// Send daily and weekly saved search digests to contractors
+	savedSearches := server.NewSavedSearchService(store, notificationService)
+	workers.Go(func(ctx context.Context) { savedSearches.RunDigests(ctx, time.Hour) })
+
+	// Remind tender participants of approaching deadlines
+	reminders := server.NewReminderService(store, notificationService, cfg.Notification.ReminderOffsets)
+	workers.Go(func(ctx context.Context) { reminders.RunReminders(ctx, time.Minute) })
+
+	// Deliver queued webhook events with retries
+	webhooks := server.NewWebhookService(store)
+	workers.Go(func(ctx context.Context) { webhooks.RunDeliveryWorker(ctx, 10*time.Second) })
+
+	// Rate limit policies shared by all replicas through Redis
+	limiter := rate_limiter.NewLimiter(redisClient, cfg.RateLimit.FailOpen)
+
 	// Initialize HTTP handlers
-	h := handlers.NewHttpHandler(db.DB, redisClient)
+	health := server.NewHealthService(db.DB, redisClient, messageBroker)
+	h := handlers.NewHttpHandler(cfg, db.DB, redisClient, redisBreaker, notificationService, server.NewRateLimitService(limiter, cfg.RateLimit.Policies), health)
+
+	// Refresh the business gauges served at /metrics
+	workers.Go(func(ctx context.Context) { h.TenderService.RunBusinessMetrics(ctx, 30*time.Second) })
 
 	// Create and run the router
-	r := api.NewGinRouter(h)
-	err := r.Run(config.GlobalConfig.AppPort)
+	router, err := api.NewGinRouter(cfg, h, notificationServer, limiter)
 	if err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		log.Fatalf("Failed to create router: %v", err)
 	}
+	// There is no WriteTimeout, which would end WebSocket and SSE streams;
+	// every write to them has a deadline of its own instead.
+	srv := &http.Server{
+		Addr:              cfg.AppPort,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// The servers report failures to serve, so the service shuts down like
+	// on a signal instead of exiting with its workers still running.
+	serveErrs := make(chan error, 2)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErrs <- fmt.Errorf("serve HTTP: %w", err)
+		}
+	}()
+	slog.Info("Serving HTTP", "addr", srv.Addr)
+
+	// Serve the gRPC API with the same services on its own port
+	grpcSrv := api.NewGRPCServer(cfg, h, notificationServer, limiter)
+	go func() {
+		grpcListener, err := net.Listen("tcp", cfg.GRPCPort)
+		if err != nil {
+			serveErrs <- fmt.Errorf("listen for gRPC: %w", err)
+			return
+		}
+		slog.Info("Serving gRPC", "addr", grpcListener.Addr().String())
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			serveErrs <- fmt.Errorf("serve gRPC: %w", err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case failure = <-serveErrs:
+		slog.Error("Server failed", "error", failure)
+	case failure = <-workers.Err():
+		slog.Error("Worker failed", "error", failure)
+	}
+	// A second signal exits right away.
+	stop()
+
+	Shutdown(srv, grpcSrv, notificationServer, health, workers, cfg.ShutdownDelay, cfg.ShutdownTimeout)
 }
 
-// InitRedis initializes the Redis client connection.
-func InitRedis() {
+// loadConfig loads the configuration from the YAML file named by CONFIG_FILE,
+// if any, and the environment, and exits listing every invalid setting.
+func loadConfig() *config.Config {
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	return cfg
+}
+
+// InitRedis initializes the Redis client connection. Every call goes through
+// a circuit breaker, and the client connects lazily, so the service starts
+// and serves from PostgreSQL while Redis is down.
+func InitRedis(cfg config.RedisConfig) {
 	redisClient = redis.NewClient(&redis.Options{
-		Addr:     config.GlobalConfig.Redis.RedisAddr, // Redis address from config
-		Password: config.GlobalConfig.Redis.RedisPass, // Redis password from config
-		DB:       0,                                   // Default DB
+		Addr:     cfg.RedisAddr, // Redis address from config
+		Password: cfg.RedisPass, // Redis password from config
+		DB:       0,             // Default DB
 	})
 
+	redisBreaker = circuit_breaker.New("redis", cfg.BreakerFailures, cfg.BreakerCooldown)
+	redisClient.AddHook(circuit_breaker.NewRedisHook(redisBreaker))
+	if err := redisotel.InstrumentTracing(redisClient); err != nil {
+		log.Fatalf("Failed to set up Redis tracing: %v", err)
+	}
+
 	// Test the connection
 	_, err := redisClient.Ping(context.Background()).Result() // Added context argument
 	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		slog.Warn("Redis is unavailable, continuing without it until it recovers", "error", err)
+		return
+	}
+	slog.Info("Connected to Redis")
+}
+
+// NewBroker creates the message broker selected by the configuration.
+func NewBroker(cfg *config.Config) broker.Broker {
+	switch cfg.Broker.Type {
+	case "rabbitmq":
+		b, err := broker.NewRabbitMQBroker(cfg.Broker.RabbitMQURL)
+		if err != nil {
+			log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+		}
+		slog.Info("Connected to RabbitMQ")
+		return b
+	case "redis":
+		return broker.NewRedisStreamBroker(redisClient, "tender-backend", cfg.InstanceID)
+	case "memory":
+		return broker.NewMemoryBroker()
+	default:
+		log.Fatalf("Unknown broker type: %s", cfg.Broker.Type)
+		return nil
 	}
-	log.Println("Connected to Redis")
 }
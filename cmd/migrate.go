@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"tender-backend/db"
+	"tender-backend/migrations"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+  up [N]         apply all pending migrations, or the next N
+  down [N]       roll back the latest migration, or the latest N
+  status         list migrations and when they were applied
+  create <name>  add empty up and down scripts to ` + migrations.Dir
+
+// runMigrate runs a "migrate" subcommand and exits.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatalf("usage: main migrate create <name>")
+		}
+		paths, err := migrations.Create(migrations.Dir, args[1])
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return
+	}
+
+	cfg := loadConfig()
+	db.ConnectDB(cfg.DB, cfg.Log.SlowQueryThreshold)
+	defer db.CloseDB()
+
+	migrator := NewMigrator()
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx, migrateSteps(args[1:], 0))
+		for _, m := range applied {
+			fmt.Printf("Applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Failed to migrate: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+	case "down":
+		reverted, err := migrator.Down(ctx, migrateSteps(args[1:], 1))
+		for _, m := range reverted {
+			fmt.Printf("Rolled back %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Failed to roll back: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
+
+// migrateSteps reads the optional step count of up and down.
+func migrateSteps(args []string, def int) int {
+	if len(args) == 0 {
+		return def
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		log.Fatalf("Invalid number of migrations %q", args[0])
+	}
+	return steps
+}
+
+// NewMigrator creates a migrator for the connected database.
+func NewMigrator() *migrations.Migrator {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		log.Fatalf("Error getting SQL db: %v", err)
+	}
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator
+}
+
+// CheckSchema refuses to serve against a schema older than this build expects.
+func CheckSchema() {
+	migrator := NewMigrator()
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatalf("%v, run \"migrate up\" before starting the server", err)
+	}
+	slog.Info("Database schema is up to date", "version", migrator.Latest())
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"tender-backend/notification"
+	"tender-backend/server"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// workerGroup runs the broker consumers and periodic jobs, which stop once
+// their context is done.
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	errs   chan error
+}
+
+func newWorkers() *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{ctx: ctx, cancel: cancel, errs: make(chan error, 1)}
+}
+
+// Go runs fn in a goroutine with the context of the workers.
+func (w *workerGroup) Go(fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// GoErr runs fn like Go. The first error a worker returns is received from
+// Err, so main can stop the service instead of running without the worker.
+func (w *workerGroup) GoErr(fn func(ctx context.Context) error) {
+	w.Go(func(ctx context.Context) {
+		if err := fn(ctx); err != nil {
+			select {
+			case w.errs <- err:
+			default:
+			}
+		}
+	})
+}
+
+// Err receives the first error returned by a worker started with GoErr.
+func (w *workerGroup) Err() <-chan error {
+	return w.errs
+}
+
+// Stop cancels the workers and waits until they returned or ctx is done.
+// Consumers return after settling the message they are processing.
+func (w *workerGroup) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown drains the service: it reports not ready and keeps serving for
+// delay, so load balancers stop sending new requests, then within timeout
+// closes the notification connections, waits for in-flight HTTP requests and
+// gRPC calls and stops the workers. The broker, Redis and database are closed
+// afterwards by main, once nothing uses them anymore.
+func Shutdown(srv *http.Server, grpcSrv *grpc.Server, ns *notification.Server, health *server.HealthService, w *workerGroup, delay, timeout time.Duration) {
+	slog.Info("Shutting down", "delay", delay, "timeout", timeout)
+
+	health.Drain()
+	time.Sleep(delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Hijacked WebSocket connections are not tracked by the HTTP server, and
+	// event and gRPC streams would only end at the timeout.
+	if err := ns.Shutdown(ctx); err != nil {
+		slog.Warn("Notification connections did not close in time", "error", err)
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP requests did not complete in time", "error", err)
+	}
+
+	if err := stopGRPC(ctx, grpcSrv); err != nil {
+		slog.Warn("gRPC calls did not complete in time", "error", err)
+	}
+
+	if err := w.Stop(ctx); err != nil {
+		slog.Warn("Workers did not stop in time", "error", err)
+	}
+
+	slog.Info("Shutdown complete")
+}
+
+// stopGRPC stops accepting gRPC calls and waits for the running ones, which
+// are cancelled once ctx is done.
+func stopGRPC(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
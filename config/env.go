@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv overrides the settings whose environment variable is set.
+func (c *Config) applyEnv(env *envReader) {
+	env.string("INSTANCE_ID", &c.InstanceID)
+	env.string("APP_PORT", &c.AppPort)
+	env.string("GRPC_PORT", &c.GRPCPort)
+	env.string("ADMIN_API_KEY", &c.AdminAPIKey)
+	env.strings("TRUSTED_PROXIES", &c.TrustedProxies)
+	env.duration("SHUTDOWN_DELAY", &c.ShutdownDelay)
+	env.duration("SHUTDOWN_TIMEOUT", &c.ShutdownTimeout)
+
+	env.string("DB_HOST", &c.DB.DBHost)
+	env.string("DB_PORT", &c.DB.DBPort)
+	env.string("DB_USER", &c.DB.DBUser)
+	env.string("DB_PASSWORD", &c.DB.DBPassword)
+	env.string("DB_NAME", &c.DB.DBName)
+	env.string("DB_SSL_MODE", &c.DB.SSLMode)
+	env.string("DB_TIMEZONE", &c.DB.TimeZone)
+
+	env.string("JWT_SECRET_KEY", &c.Auth.JWTSecret)
+	env.duration("JWT_TOKEN_LIFETIME", &c.Auth.TokenLifetime)
+
+	env.string("REDIS_ADDR", &c.Redis.RedisAddr)
+	env.string("REDIS_PASS", &c.Redis.RedisPass)
+	env.int("REDIS_BREAKER_FAILURES", &c.Redis.BreakerFailures)
+	env.duration("REDIS_BREAKER_COOLDOWN", &c.Redis.BreakerCooldown)
+
+	env.duration("CACHE_TTL", &c.Cache.TTL)
+	env.int("CACHE_LOCAL_SIZE", &c.Cache.LocalSize)
+	env.duration("CACHE_LOCAL_TTL", &c.Cache.LocalTTL)
+
+	env.string("BROKER_TYPE", &c.Broker.Type)
+	env.string("RABBITMQ_URL", &c.Broker.RabbitMQURL)
+
+	env.string("SMTP_HOST", &c.SMTP.Host)
+	env.string("SMTP_PORT", &c.SMTP.Port)
+	env.string("SMTP_USERNAME", &c.SMTP.Username)
+	env.string("SMTP_PASSWORD", &c.SMTP.Password)
+	env.string("SMTP_FROM", &c.SMTP.From)
+
+	env.duration("NOTIFICATION_FALLBACK_DELAY", &c.Notification.FallbackDelay)
+	env.durations("DEADLINE_REMINDER_OFFSETS", &c.Notification.ReminderOffsets)
+
+	env.bool("RATE_LIMIT_FAIL_OPEN", &c.RateLimit.FailOpen)
+	env.string("RATE_LIMIT_POLICIES_FILE", &c.RateLimit.PoliciesFile)
+	env.strings("RATE_LIMIT_API_KEYS", &c.RateLimit.APIKeys)
+
+	env.string("LOG_LEVEL", &c.Log.Level)
+	env.string("LOG_FORMAT", &c.Log.Format)
+	env.duration("LOG_SLOW_QUERY_THRESHOLD", &c.Log.SlowQueryThreshold)
+
+	env.string("TRACING_EXPORTER", &c.Tracing.Exporter)
+	env.string("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+}
+
+// envReader overrides settings from environment variables. Empty variables
+// are treated as unset, and malformed ones are collected in errs instead of
+// falling back to the default, so a typo never goes unnoticed.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(key))
+	return value, value != ""
+}
+
+func (r *envReader) fail(key, value, want string) {
+	r.errs = append(r.errs, fmt.Errorf("%s: %q is not %s", key, value, want))
+}
+
+func (r *envReader) string(key string, dst *string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = value
+	}
+}
+
+// strings reads a comma separated list such as "10.0.0.0/8,192.168.1.2".
+func (r *envReader) strings(key string, dst *[]string) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	*dst = values
+}
+
+// bool reads a boolean such as "true" or "0".
+func (r *envReader) bool(key string, dst *bool) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.fail(key, value, "a boolean")
+		return
+	}
+	*dst = b
+}
+
+func (r *envReader) int(key string, dst *int) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(key, value, "an integer")
+		return
+	}
+	*dst = i
+}
+
+// duration reads a duration such as "5m".
+func (r *envReader) duration(key string, dst *time.Duration) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.fail(key, value, "a duration such as 30s or 5m")
+		return
+	}
+	*dst = d
+}
+
+// durations reads a comma separated list of durations such as "72h,24h,1h".
+func (r *envReader) durations(key string, dst *[]time.Duration) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			r.fail(key, value, "a comma separated list of durations such as 72h,24h,1h")
+			return
+		}
+		durations = append(durations, d)
+	}
+	*dst = durations
+}
@@ -2,15 +2,10 @@ package config
 
 import (
 	"errors"
-	"regexp"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-func IsValidEmail(email string) bool {
-	return regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`).MatchString(email)
-}
-
 func IsValidPassword(password string) error {
 	if len(password) < 5 {
 		return errors.New("password must be at least 5 characters long")
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"tender-backend/cache"
+	"time"
+
+	"gorm.io/gorm/utils"
+)
+
+// Validate reports every invalid or missing setting at once, named by its
+// environment variable.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(!strings.ContainsAny(c.InstanceID, " *"), "INSTANCE_ID: cannot contain spaces or '*'")
+	check(c.AppPort != "", "APP_PORT: is required")
+	check(c.GRPCPort != "", "GRPC_PORT: is required")
+	check(c.GRPCPort != c.AppPort, "GRPC_PORT: cannot be the same as APP_PORT")
+	check(c.ShutdownDelay >= 0, "SHUTDOWN_DELAY: cannot be negative")
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT: must be positive")
+	for _, proxy := range c.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES: %q is not an IP or CIDR", proxy)
+	}
+
+	check(c.DB.DBHost != "", "DB_HOST: is required")
+	check(isPort(c.DB.DBPort), "DB_PORT: %q is not a port", c.DB.DBPort)
+	check(c.DB.DBUser != "", "DB_USER: is required")
+	check(c.DB.DBName != "", "DB_NAME: is required")
+	check(utils.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, c.DB.SSLMode),
+		"DB_SSL_MODE: %q is not disable, allow, prefer, require, verify-ca or verify-full", c.DB.SSLMode)
+	if _, err := time.LoadLocation(c.DB.TimeZone); err != nil || c.DB.TimeZone == "" {
+		errs = append(errs, fmt.Errorf("DB_TIMEZONE: %q is not a time zone", c.DB.TimeZone))
+	}
+
+	check(c.Auth.JWTSecret != "", "JWT_SECRET_KEY: is required")
+	check(c.Auth.TokenLifetime > 0, "JWT_TOKEN_LIFETIME: must be positive")
+
+	check(c.Redis.RedisAddr != "", "REDIS_ADDR: is required")
+	check(c.Redis.BreakerFailures > 0, "REDIS_BREAKER_FAILURES: must be positive")
+	check(c.Redis.BreakerCooldown > 0, "REDIS_BREAKER_COOLDOWN: must be positive")
+
+	check(c.Cache.TTL > 0, "CACHE_TTL: must be positive")
+	check(c.Cache.TTL < cache.TagTTL, "CACHE_TTL: must be shorter than %s, how long invalidations are remembered", cache.TagTTL)
+	check(c.Cache.LocalSize >= 0, "CACHE_LOCAL_SIZE: cannot be negative")
+	check(c.Cache.LocalSize == 0 || c.Cache.LocalTTL > 0, "CACHE_LOCAL_TTL: must be positive while the local cache is enabled")
+
+	switch c.Broker.Type {
+	case "rabbitmq":
+		u, err := url.Parse(c.Broker.RabbitMQURL)
+		check(err == nil && (u.Scheme == "amqp" || u.Scheme == "amqps") && u.Host != "",
+			"RABBITMQ_URL: %q is not an amqp:// or amqps:// URL", redactURL(c.Broker.RabbitMQURL))
+	case "redis", "memory":
+	default:
+		errs = append(errs, fmt.Errorf("BROKER_TYPE: %q is not rabbitmq, redis or memory", c.Broker.Type))
+	}
+
+	check(c.SMTP.Host == "" || isPort(c.SMTP.Port), "SMTP_PORT: %q is not a port", c.SMTP.Port)
+	check(c.SMTP.Host == "" || c.SMTP.From != "", "SMTP_FROM: is required when SMTP_HOST is set")
+
+	check(c.Notification.FallbackDelay > 0, "NOTIFICATION_FALLBACK_DELAY: must be positive")
+	for _, offset := range c.Notification.ReminderOffsets {
+		check(offset > 0, "DEADLINE_REMINDER_OFFSETS: %s is not positive", offset)
+	}
+
+	if err := c.RateLimit.Policies.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("rate limit policies: %w", err))
+	}
+
+	check(utils.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(c.Log.Level)),
+		"LOG_LEVEL: %q is not debug, info, warn or error", c.Log.Level)
+	check(utils.Contains([]string{"json", "text"}, strings.ToLower(c.Log.Format)),
+		"LOG_FORMAT: %q is not json or text", c.Log.Format)
+	check(c.Log.SlowQueryThreshold >= 0, "LOG_SLOW_QUERY_THRESHOLD: cannot be negative")
+
+	check(utils.Contains([]string{"otlp", "stdout", "none"}, strings.ToLower(c.Tracing.Exporter)),
+		"TRACING_EXPORTER: %q is not otlp, stdout or none", c.Tracing.Exporter)
+	check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME: is required")
+
+	return errors.Join(errs...)
+}
+
+func isPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port <= 65535
+}
+
+// redactURL hides the password of a URL in error messages.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<unparsable>"
+	}
+	return u.Redacted()
+}
@@ -1,40 +1,163 @@
 package custom_errors
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+)
+
+// Codes identify the kind of an error in problem responses. Unlike messages
+// they never change, so clients branch on them.
+const (
+	CodeInvalidRequest          = "invalid_request"
+	CodeInvalidPayload          = "invalid_payload"
+	CodeInvalidParameter        = "invalid_parameter"
+	CodeValidationFailed        = "validation_failed"
+	CodeUnauthorized            = "unauthorized"
+	CodeInvalidCredentials      = "invalid_credentials"
+	CodeForbidden               = "forbidden"
+	CodeNotFound                = "not_found"
+	CodeUserNotFound            = "user_not_found"
+	CodeTenderNotFound          = "tender_not_found"
+	CodeBidNotFound             = "bid_not_found"
+	CodeEmailTaken              = "email_taken"
+	CodeTenderNotOpen           = "tender_not_open"
+	CodeInvalidStatusTransition = "invalid_status_transition"
+	CodeResourceModified        = "resource_modified"
+	CodePreconditionRequired    = "precondition_required"
+	CodeRateLimited             = "rate_limited"
+	CodeServiceUnavailable      = "service_unavailable"
+	CodeInternal                = "internal_error"
+)
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	// Field is the JSON path of the field, e.g. "budget" or "channels[1]".
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
 
 type AppError struct {
+	// Code is the stable machine-readable kind of the error.
+	Code       string
 	Message    string
 	StatusCode int
+	// Fields lists the invalid fields of validation errors.
+	Fields []FieldError
+	// Err is the cause. It is logged, but never shown to clients.
+	Err error
 }
 
 func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
 	return e.Message
 }
 
-func NewBadRequestError(message string) *AppError {
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode returns a copy of the error with a more specific code.
+func (e *AppError) WithCode(code string) *AppError {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// WithField returns a copy of the error with one more invalid field.
+func (e *AppError) WithField(field, message string) *AppError {
+	c := *e
+	c.Fields = append(append([]FieldError(nil), e.Fields...), FieldError{Field: field, Message: message})
+	return &c
+}
+
+// Wrap returns a copy of the error caused by err.
+func (e *AppError) Wrap(err error) *AppError {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// New creates an error with the given status, code and client-facing message.
+func New(statusCode int, code, message string) *AppError {
 	return &AppError{
+		Code:       code,
 		Message:    message,
-		StatusCode: http.StatusBadRequest,
+		StatusCode: statusCode,
 	}
 }
 
-func NewNotFoundError(message string) *AppError {
-	return &AppError{
-		Message:    message,
-		StatusCode: http.StatusNotFound,
+// From returns err as an AppError. Errors that are not AppErrors become
+// internal errors caused by err.
+func From(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
 	}
+	return NewAppError(err)
+}
+
+func NewBadRequestError(message string) *AppError {
+	return New(http.StatusBadRequest, CodeInvalidRequest, message)
+}
+
+// NewInvalidPayloadError reports a request body that could not be decoded.
+// The decoding error only describes the body, so it is shown to the client.
+func NewInvalidPayloadError(err error) *AppError {
+	return New(http.StatusBadRequest, CodeInvalidPayload, "Invalid request payload: "+err.Error())
+}
+
+// NewInvalidParameterError reports a malformed path or query parameter.
+func NewInvalidParameterError(message string) *AppError {
+	return New(http.StatusBadRequest, CodeInvalidParameter, message)
+}
+
+// NewValidationError reports every invalid field of a request at once.
+func NewValidationError(fields ...FieldError) *AppError {
+	err := New(http.StatusBadRequest, CodeValidationFailed, "Request validation failed")
+	err.Fields = fields
+	return err
+}
+
+func NewUnauthorizedError(message string) *AppError {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func NewForbiddenError(message string) *AppError {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+func NewNotFoundError(message string) *AppError {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+func NewConflictError(code, message string) *AppError {
+	return New(http.StatusConflict, code, message)
+}
+
+func NewPreconditionFailedError(message string) *AppError {
+	return New(http.StatusPreconditionFailed, CodeResourceModified, message)
+}
+
+func NewPreconditionRequiredError(message string) *AppError {
+	return New(http.StatusPreconditionRequired, CodePreconditionRequired, message)
+}
+
+func NewTooManyRequestsError(message string) *AppError {
+	return New(http.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+func NewServiceUnavailableError(message string) *AppError {
+	return New(http.StatusServiceUnavailable, CodeServiceUnavailable, message)
 }
 
 func NewGenericError(message string) *AppError {
-	return &AppError{
-		Message:    message,
-		StatusCode: http.StatusInternalServerError,
-	}
+	return New(http.StatusInternalServerError, CodeInternal, message)
 }
 
+// NewAppError reports an unexpected failure. Clients only see a generic
+// message; err is logged.
 func NewAppError(err error) *AppError {
-	return &AppError{
-		Message:    err.Error(),
-		StatusCode: http.StatusInternalServerError,
-	}
+	return NewGenericError("Internal server error").Wrap(err)
 }
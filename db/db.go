@@ -1,50 +1,34 @@
 package db
 
 import (
-	"fmt"
 	"log"
+	"log/slog"
 	"tender-backend/config"
-	"tender-backend/model"
+	"time"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func ConnectDB() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		config.GlobalConfig.DB.DBHost,
-		config.GlobalConfig.DB.DBUser,
-		config.GlobalConfig.DB.DBPassword,
-		config.GlobalConfig.DB.DBName,
-		config.GlobalConfig.DB.DBPort,
-		config.GlobalConfig.DB.SSLMode,
-		config.GlobalConfig.DB.TimeZone,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+// ConnectDB opens the database. Queries slower than slowQueryThreshold are
+// logged as warnings.
+func ConnectDB(cfg config.DBConfig, slowQueryThreshold time.Duration) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		Logger: newSlogLogger(slowQueryThreshold),
 	})
 
 	if err != nil {
 		log.Fatalf("Error connecting to the database: %v", err)
 	}
 
-	DB = db
-	fmt.Println("Connected to the database")
-
-	if err := DB.AutoMigrate(&model.User{}, &model.Tender{}, &model.Bid{}, &model.Notification{}); err != nil {
-		log.Fatalf("Error migrating database: %v", err)
+	if err := db.Use(tracingPlugin{}); err != nil {
+		log.Fatalf("Error setting up query tracing: %v", err)
 	}
-	fmt.Println("Database migrated")
+
+	DB = db
+	slog.Info("Connected to the database")
 }
 
 func CloseDB() {
@@ -55,5 +39,5 @@ func CloseDB() {
 	if err := sqlDB.Close(); err != nil {
 		log.Fatalf("Error closing the database: %v", err)
 	}
-	fmt.Println("Database connection closed")
+	slog.Info("Database connection closed")
 }
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slogLogger writes GORM logs to slog with the context of the query, so they
+// carry its request ID. Queries are logged at debug level, slow ones at warn
+// and failed ones at error; missing records are not errors.
+type slogLogger struct {
+	slowThreshold time.Duration
+}
+
+func newSlogLogger(slowThreshold time.Duration) logger.Interface {
+	return slogLogger{slowThreshold: slowThreshold}
+}
+
+// LogMode is a no-op, the level of the slog logger applies.
+func (l slogLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l slogLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "Query failed", "sql", sql, "rows", rows, "duration", elapsed, "error", err)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "Slow query", "sql", sql, "rows", rows, "duration", elapsed)
+	case slog.Default().Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		slog.DebugContext(ctx, "Query", "sql", sql, "rows", rows, "duration", elapsed)
+	}
+}
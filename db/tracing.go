@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"tender-backend/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = tracing.Tracer("db")
+
+// parentContextKey keeps the context a query started with, which is restored
+// once its span ended, so later queries of a reused statement are not its children.
+const parentContextKey = "tracing:parent_context"
+
+// tracingPlugin records a span for every query, a child of the span in the
+// context the query runs with, e.g. the one of the HTTP request.
+type tracingPlugin struct{}
+
+func (tracingPlugin) Name() string {
+	return "tracing"
+}
+
+func (tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		tx.InstanceSet(parentContextKey, tx.Statement.Context)
+		ctx, _ := tracer.Start(tx.Statement.Context, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(operation)))
+		tx.Statement.Context = ctx
+	}
+}
+
+func endSpan(tx *gorm.DB) {
+	span := trace.SpanFromContext(tx.Statement.Context)
+	if parent, ok := tx.InstanceGet(parentContextKey); ok {
+		tx.Statement.Context = parent.(context.Context)
+	}
+	if !span.IsRecording() {
+		return
+	}
+	defer span.End()
+
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBSQLTable(tx.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBStatement(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+
+	if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,284 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.1
+// source: bid.proto
+
+package gen_proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BidService_CreateBid_FullMethodName          = "/BidService/CreateBid"
+	BidService_GetBid_FullMethodName             = "/BidService/GetBid"
+	BidService_ListTenderBids_FullMethodName     = "/BidService/ListTenderBids"
+	BidService_ListContractorBids_FullMethodName = "/BidService/ListContractorBids"
+	BidService_DeleteBid_FullMethodName          = "/BidService/DeleteBid"
+)
+
+// BidServiceClient is the client API for BidService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BidService manages the bids of contractors on tenders.
+type BidServiceClient interface {
+	// CreateBid places a bid of the calling contractor on an open tender.
+	CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	GetBid(ctx context.Context, in *GetBidRequest, opts ...grpc.CallOption) (*Bid, error)
+	// ListTenderBids returns the bids on a tender, for clients.
+	ListTenderBids(ctx context.Context, in *ListTenderBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error)
+	// ListContractorBids returns the bids of the calling contractor.
+	ListContractorBids(ctx context.Context, in *ListContractorBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error)
+	DeleteBid(ctx context.Context, in *DeleteBidRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type bidServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBidServiceClient(cc grpc.ClientConnInterface) BidServiceClient {
+	return &bidServiceClient{cc}
+}
+
+func (c *bidServiceClient) CreateBid(ctx context.Context, in *CreateBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_CreateBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) GetBid(ctx context.Context, in *GetBidRequest, opts ...grpc.CallOption) (*Bid, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, BidService_GetBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListTenderBids(ctx context.Context, in *ListTenderBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBidsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListTenderBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) ListContractorBids(ctx context.Context, in *ListContractorBidsRequest, opts ...grpc.CallOption) (*ListBidsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBidsResponse)
+	err := c.cc.Invoke(ctx, BidService_ListContractorBids_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bidServiceClient) DeleteBid(ctx context.Context, in *DeleteBidRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, BidService_DeleteBid_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BidServiceServer is the server API for BidService service.
+// All implementations must embed UnimplementedBidServiceServer
+// for forward compatibility.
+//
+// BidService manages the bids of contractors on tenders.
+type BidServiceServer interface {
+	// CreateBid places a bid of the calling contractor on an open tender.
+	CreateBid(context.Context, *CreateBidRequest) (*Bid, error)
+	GetBid(context.Context, *GetBidRequest) (*Bid, error)
+	// ListTenderBids returns the bids on a tender, for clients.
+	ListTenderBids(context.Context, *ListTenderBidsRequest) (*ListBidsResponse, error)
+	// ListContractorBids returns the bids of the calling contractor.
+	ListContractorBids(context.Context, *ListContractorBidsRequest) (*ListBidsResponse, error)
+	DeleteBid(context.Context, *DeleteBidRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+// UnimplementedBidServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBidServiceServer struct{}
+
+func (UnimplementedBidServiceServer) CreateBid(context.Context, *CreateBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBid not implemented")
+}
+func (UnimplementedBidServiceServer) GetBid(context.Context, *GetBidRequest) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBid not implemented")
+}
+func (UnimplementedBidServiceServer) ListTenderBids(context.Context, *ListTenderBidsRequest) (*ListBidsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenderBids not implemented")
+}
+func (UnimplementedBidServiceServer) ListContractorBids(context.Context, *ListContractorBidsRequest) (*ListBidsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContractorBids not implemented")
+}
+func (UnimplementedBidServiceServer) DeleteBid(context.Context, *DeleteBidRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBid not implemented")
+}
+func (UnimplementedBidServiceServer) mustEmbedUnimplementedBidServiceServer() {}
+func (UnimplementedBidServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeBidServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BidServiceServer will
+// result in compilation errors.
+type UnsafeBidServiceServer interface {
+	mustEmbedUnimplementedBidServiceServer()
+}
+
+func RegisterBidServiceServer(s grpc.ServiceRegistrar, srv BidServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBidServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BidService_ServiceDesc, srv)
+}
+
+func _BidService_CreateBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).CreateBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_CreateBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).CreateBid(ctx, req.(*CreateBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_GetBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).GetBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_GetBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).GetBid(ctx, req.(*GetBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListTenderBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenderBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListTenderBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListTenderBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListTenderBids(ctx, req.(*ListTenderBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_ListContractorBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContractorBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).ListContractorBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_ListContractorBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).ListContractorBids(ctx, req.(*ListContractorBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BidService_DeleteBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BidServiceServer).DeleteBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BidService_DeleteBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BidServiceServer).DeleteBid(ctx, req.(*DeleteBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BidService_ServiceDesc is the grpc.ServiceDesc for BidService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BidService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "BidService",
+	HandlerType: (*BidServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBid",
+			Handler:    _BidService_CreateBid_Handler,
+		},
+		{
+			MethodName: "GetBid",
+			Handler:    _BidService_GetBid_Handler,
+		},
+		{
+			MethodName: "ListTenderBids",
+			Handler:    _BidService_ListTenderBids_Handler,
+		},
+		{
+			MethodName: "ListContractorBids",
+			Handler:    _BidService_ListContractorBids_Handler,
+		},
+		{
+			MethodName: "DeleteBid",
+			Handler:    _BidService_DeleteBid_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bid.proto",
+}
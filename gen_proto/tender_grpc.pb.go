@@ -0,0 +1,318 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.1
+// source: tender.proto
+
+package gen_proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TenderService_CreateTender_FullMethodName       = "/TenderService/CreateTender"
+	TenderService_GetTender_FullMethodName          = "/TenderService/GetTender"
+	TenderService_ListTenders_FullMethodName        = "/TenderService/ListTenders"
+	TenderService_UpdateTenderStatus_FullMethodName = "/TenderService/UpdateTenderStatus"
+	TenderService_DeleteTender_FullMethodName       = "/TenderService/DeleteTender"
+	TenderService_AwardTender_FullMethodName        = "/TenderService/AwardTender"
+)
+
+// TenderServiceClient is the client API for TenderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TenderService manages tenders. Reading them is public, changing them is
+// limited to clients, who can only change their own tenders.
+type TenderServiceClient interface {
+	CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	GetTender(ctx context.Context, in *GetTenderRequest, opts ...grpc.CallOption) (*Tender, error)
+	ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error)
+	UpdateTenderStatus(ctx context.Context, in *UpdateTenderStatusRequest, opts ...grpc.CallOption) (*Tender, error)
+	DeleteTender(ctx context.Context, in *DeleteTenderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AwardTender(ctx context.Context, in *AwardTenderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type tenderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTenderServiceClient(cc grpc.ClientConnInterface) TenderServiceClient {
+	return &tenderServiceClient{cc}
+}
+
+func (c *tenderServiceClient) CreateTender(ctx context.Context, in *CreateTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_CreateTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) GetTender(ctx context.Context, in *GetTenderRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_GetTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) ListTenders(ctx context.Context, in *ListTendersRequest, opts ...grpc.CallOption) (*ListTendersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTendersResponse)
+	err := c.cc.Invoke(ctx, TenderService_ListTenders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) UpdateTenderStatus(ctx context.Context, in *UpdateTenderStatusRequest, opts ...grpc.CallOption) (*Tender, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tender)
+	err := c.cc.Invoke(ctx, TenderService_UpdateTenderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) DeleteTender(ctx context.Context, in *DeleteTenderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TenderService_DeleteTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tenderServiceClient) AwardTender(ctx context.Context, in *AwardTenderRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TenderService_AwardTender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TenderServiceServer is the server API for TenderService service.
+// All implementations must embed UnimplementedTenderServiceServer
+// for forward compatibility.
+//
+// TenderService manages tenders. Reading them is public, changing them is
+// limited to clients, who can only change their own tenders.
+type TenderServiceServer interface {
+	CreateTender(context.Context, *CreateTenderRequest) (*Tender, error)
+	GetTender(context.Context, *GetTenderRequest) (*Tender, error)
+	ListTenders(context.Context, *ListTendersRequest) (*ListTendersResponse, error)
+	UpdateTenderStatus(context.Context, *UpdateTenderStatusRequest) (*Tender, error)
+	DeleteTender(context.Context, *DeleteTenderRequest) (*emptypb.Empty, error)
+	AwardTender(context.Context, *AwardTenderRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+// UnimplementedTenderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTenderServiceServer struct{}
+
+func (UnimplementedTenderServiceServer) CreateTender(context.Context, *CreateTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTender not implemented")
+}
+func (UnimplementedTenderServiceServer) GetTender(context.Context, *GetTenderRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTender not implemented")
+}
+func (UnimplementedTenderServiceServer) ListTenders(context.Context, *ListTendersRequest) (*ListTendersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenders not implemented")
+}
+func (UnimplementedTenderServiceServer) UpdateTenderStatus(context.Context, *UpdateTenderStatusRequest) (*Tender, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenderStatus not implemented")
+}
+func (UnimplementedTenderServiceServer) DeleteTender(context.Context, *DeleteTenderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTender not implemented")
+}
+func (UnimplementedTenderServiceServer) AwardTender(context.Context, *AwardTenderRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AwardTender not implemented")
+}
+func (UnimplementedTenderServiceServer) mustEmbedUnimplementedTenderServiceServer() {}
+func (UnimplementedTenderServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeTenderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TenderServiceServer will
+// result in compilation errors.
+type UnsafeTenderServiceServer interface {
+	mustEmbedUnimplementedTenderServiceServer()
+}
+
+func RegisterTenderServiceServer(s grpc.ServiceRegistrar, srv TenderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTenderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TenderService_ServiceDesc, srv)
+}
+
+func _TenderService_CreateTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).CreateTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_CreateTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).CreateTender(ctx, req.(*CreateTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_GetTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).GetTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_GetTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).GetTender(ctx, req.(*GetTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_ListTenders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTendersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).ListTenders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_ListTenders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).ListTenders(ctx, req.(*ListTendersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_UpdateTenderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).UpdateTenderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_UpdateTenderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).UpdateTenderStatus(ctx, req.(*UpdateTenderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_DeleteTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).DeleteTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_DeleteTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).DeleteTender(ctx, req.(*DeleteTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TenderService_AwardTender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AwardTenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TenderServiceServer).AwardTender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TenderService_AwardTender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenderServiceServer).AwardTender(ctx, req.(*AwardTenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TenderService_ServiceDesc is the grpc.ServiceDesc for TenderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TenderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "TenderService",
+	HandlerType: (*TenderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTender",
+			Handler:    _TenderService_CreateTender_Handler,
+		},
+		{
+			MethodName: "GetTender",
+			Handler:    _TenderService_GetTender_Handler,
+		},
+		{
+			MethodName: "ListTenders",
+			Handler:    _TenderService_ListTenders_Handler,
+		},
+		{
+			MethodName: "UpdateTenderStatus",
+			Handler:    _TenderService_UpdateTenderStatus_Handler,
+		},
+		{
+			MethodName: "DeleteTender",
+			Handler:    _TenderService_DeleteTender_Handler,
+		},
+		{
+			MethodName: "AwardTender",
+			Handler:    _TenderService_AwardTender_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tender.proto",
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"tender-backend/gen_proto"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/server"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// BidServer serves gen_proto.BidService with the bid service.
+type BidServer struct {
+	gen_proto.UnimplementedBidServiceServer
+	bids *server.BidService
+}
+
+func NewBidServer(bids *server.BidService) *BidServer {
+	return &BidServer{bids: bids}
+}
+
+func (s *BidServer) CreateBid(ctx context.Context, req *gen_proto.CreateBidRequest) (*gen_proto.Bid, error) {
+	bid, err := s.bids.CreateBid(ctx, &request_model.CreateBidReq{
+		Price:        req.GetPrice(),
+		DeliveryTime: int(req.GetDeliveryTime()),
+		Comments:     req.GetComments(),
+	}, req.GetTenderId(), userID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return bidProto(bid), nil
+}
+
+func (s *BidServer) GetBid(ctx context.Context, req *gen_proto.GetBidRequest) (*gen_proto.Bid, error) {
+	bid, err := s.bids.GetBidByID(ctx, req.GetBidId(), req.GetTenderId())
+	if err != nil {
+		return nil, err
+	}
+
+	return bidProto(bid), nil
+}
+
+func (s *BidServer) ListTenderBids(ctx context.Context, req *gen_proto.ListTenderBidsRequest) (*gen_proto.ListBidsResponse, error) {
+	bids, err := s.bids.GetAllBids(ctx, req.GetTenderId())
+	if err != nil {
+		return nil, err
+	}
+
+	return bidsProto(bids), nil
+}
+
+func (s *BidServer) ListContractorBids(ctx context.Context, req *gen_proto.ListContractorBidsRequest) (*gen_proto.ListBidsResponse, error) {
+	bids, err := s.bids.GetContractorBids(ctx, userID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return bidsProto(bids), nil
+}
+
+func (s *BidServer) DeleteBid(ctx context.Context, req *gen_proto.DeleteBidRequest) (*emptypb.Empty, error) {
+	versions, err := requireVersion(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bids.DeleteBid(ctx, req.GetBidId(), userID(ctx), versions); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func bidProto(bid *model.Bid) *gen_proto.Bid {
+	return &gen_proto.Bid{
+		Id:           bid.ID,
+		TenderId:     bid.TenderID,
+		ContractorId: bid.ContractorID,
+		Price:        bid.Price,
+		DeliveryTime: int32(bid.DeliveryTime),
+		Comments:     bid.Comments,
+		Status:       bid.Status,
+		Version:      bid.Version,
+		CreatedAt:    timestamp(bid.CreatedAt),
+		UpdatedAt:    timestamp(bid.UpdatedAt),
+	}
+}
+
+func bidsProto(bids []model.Bid) *gen_proto.ListBidsResponse {
+	res := &gen_proto.ListBidsResponse{Bids: make([]*gen_proto.Bid, 0, len(bids))}
+	for i := range bids {
+		res.Bids = append(res.Bids, bidProto(&bids[i]))
+	}
+	return res
+}
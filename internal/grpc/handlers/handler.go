@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"tender-backend/custom_errors"
+	"tender-backend/internal/grpc/interceptor"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userID is the ID of the calling user, 0 for public methods called without
+// a token.
+func userID(ctx context.Context) int64 {
+	claims, ok := interceptor.Claims(ctx)
+	if !ok {
+		return 0
+	}
+	return claims.UserID
+}
+
+// requireVersion returns the versions a change applies to. Unlike the
+// If-Match header of the REST API, it cannot match any version.
+func requireVersion(version int64) ([]int64, *custom_errors.AppError) {
+	if version == 0 {
+		return nil, custom_errors.NewPreconditionRequiredError("version is required")
+	}
+	return []int64{version}, nil
+}
+
+// timeOf converts an optional timestamp, zero when unset.
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// timestamp converts a time, nil when zero.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"tender-backend/gen_proto"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/server"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TenderServer serves gen_proto.TenderService with the tender service.
+type TenderServer struct {
+	gen_proto.UnimplementedTenderServiceServer
+	tenders *server.TenderService
+}
+
+func NewTenderServer(tenders *server.TenderService) *TenderServer {
+	return &TenderServer{tenders: tenders}
+}
+
+func (s *TenderServer) CreateTender(ctx context.Context, req *gen_proto.CreateTenderRequest) (*gen_proto.Tender, error) {
+	tender, err := s.tenders.CreateTender(ctx, &request_model.CreateTenderReq{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Deadline:    timeOf(req.GetDeadline()),
+		Budget:      req.GetBudget(),
+		Category:    req.GetCategory(),
+	}, userID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return tenderProto(tender), nil
+}
+
+func (s *TenderServer) GetTender(ctx context.Context, req *gen_proto.GetTenderRequest) (*gen_proto.Tender, error) {
+	tender, err := s.tenders.GetTenderById(ctx, req.GetTenderId())
+	if err != nil {
+		return nil, err
+	}
+
+	return tenderProto(tender), nil
+}
+
+func (s *TenderServer) ListTenders(ctx context.Context, req *gen_proto.ListTendersRequest) (*gen_proto.ListTendersResponse, error) {
+	tenders, err := s.tenders.GetTenders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &gen_proto.ListTendersResponse{Tenders: make([]*gen_proto.Tender, 0, len(tenders))}
+	for i := range tenders {
+		res.Tenders = append(res.Tenders, tenderProto(&tenders[i]))
+	}
+	return res, nil
+}
+
+func (s *TenderServer) UpdateTenderStatus(ctx context.Context, req *gen_proto.UpdateTenderStatusRequest) (*gen_proto.Tender, error) {
+	versions, err := requireVersion(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := s.tenders.UpdateTender(ctx, req.GetTenderId(), userID(ctx), &request_model.UpdateTenderReq{Status: req.GetStatus()}, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	return tenderProto(tender), nil
+}
+
+func (s *TenderServer) DeleteTender(ctx context.Context, req *gen_proto.DeleteTenderRequest) (*emptypb.Empty, error) {
+	versions, err := requireVersion(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tenders.DeleteTender(ctx, req.GetTenderId(), userID(ctx), versions); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TenderServer) AwardTender(ctx context.Context, req *gen_proto.AwardTenderRequest) (*emptypb.Empty, error) {
+	if err := s.tenders.AwardTender(ctx, req.GetTenderId(), userID(ctx), req.GetBidId()); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func tenderProto(tender *model.Tender) *gen_proto.Tender {
+	return &gen_proto.Tender{
+		Id:                  tender.ID,
+		ClientId:            tender.ClientID,
+		Title:               tender.Title,
+		Description:         tender.Description,
+		Deadline:            timestamp(tender.Deadline),
+		Budget:              tender.Budget,
+		Category:            tender.Category,
+		Status:              tender.Status,
+		AwardedContractorId: tender.AwardedContractorID,
+		Version:             tender.Version,
+		UpdatedAt:           timestamp(tender.UpdatedAt),
+	}
+}
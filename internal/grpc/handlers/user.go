@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"tender-backend/custom_errors"
+	"tender-backend/gen_proto"
+	"tender-backend/internal/http/token"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/notification"
+	"tender-backend/server"
+	"tender-backend/web_socket"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// UserServer serves gen_proto.UserService with the user service, and streams
+// notifications through the notification server.
+type UserServer struct {
+	gen_proto.UnimplementedUserServiceServer
+	users         *server.UserService
+	tokens        *token.Manager
+	notifications *notification.Server
+}
+
+func NewUserServer(users *server.UserService, tokens *token.Manager, notifications *notification.Server) *UserServer {
+	return &UserServer{
+		users:         users,
+		tokens:        tokens,
+		notifications: notifications,
+	}
+}
+
+func (s *UserServer) Register(ctx context.Context, req *gen_proto.RegisterRequest) (*gen_proto.AuthResponse, error) {
+	user, err := s.users.CreateUser(ctx, &request_model.CreateUserReq{
+		FullName: req.GetFullName(),
+		Password: req.GetPassword(),
+		Email:    req.GetEmail(),
+		Username: req.GetUsername(),
+		Role:     req.GetRole(),
+		Locale:   req.GetLocale(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authResponse(user)
+}
+
+func (s *UserServer) Login(ctx context.Context, req *gen_proto.LoginRequest) (*gen_proto.AuthResponse, error) {
+	user, err := s.users.Authenticate(ctx, &request_model.LoginUserReq{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authResponse(user)
+}
+
+func (s *UserServer) authResponse(user *model.User) (*gen_proto.AuthResponse, error) {
+	tkn, err := s.tokens.GenerateJWT(user.ID, user.Role, user.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen_proto.AuthResponse{Token: tkn, Role: user.Role}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *gen_proto.GetUserRequest) (*gen_proto.User, error) {
+	user, err := s.users.GetUserByID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return userProto(user), nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *gen_proto.UpdateUserRequest) (*gen_proto.User, error) {
+	user, err := s.users.UpdateUser(ctx, &request_model.UpdateUserReq{
+		FullName: req.GetFullName(),
+		Email:    req.GetEmail(),
+		Locale:   req.GetLocale(),
+	}, userID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return userProto(user), nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *gen_proto.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.users.DeleteUser(ctx, userID(ctx)); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) StreamNotifications(req *gen_proto.StreamNotificationsRequest, stream grpc.ServerStreamingServer[gen_proto.Notification]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	conn := &streamConnection{stream: stream, cancel: cancel}
+	err := s.notifications.Stream(ctx, "grpc", userID(ctx), conn)
+	if errors.Is(err, notification.ErrShuttingDown) {
+		return custom_errors.NewServiceUnavailableError("Server is shutting down")
+	}
+	if conn.isStalled() {
+		return status.Error(codes.ResourceExhausted, "Client stopped receiving notifications")
+	}
+	return err
+}
+
+var errStreamStalled = errors.New("client stopped receiving notifications")
+
+// streamConnection pushes notifications to a gRPC stream, which cannot be sent
+// to from several goroutines at once. A send that blocks longer than
+// web_socket.WriteTimeout, because the client stopped receiving, ends the
+// stream instead of holding up the delivery to other clients.
+type streamConnection struct {
+	mu      sync.Mutex
+	stream  grpc.ServerStreamingServer[gen_proto.Notification]
+	cancel  context.CancelFunc
+	stalled bool
+}
+
+func (c *streamConnection) Send(notification *gen_proto.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The blocked send of a stalled stream may still be running.
+	if c.stalled {
+		return errStreamStalled
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.stream.Send(notification)
+	}()
+
+	timer := time.NewTimer(web_socket.WriteTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		// Ending the handler cancels the stream, which unblocks the send.
+		c.stalled = true
+		c.cancel()
+		return errStreamStalled
+	}
+}
+
+func (c *streamConnection) isStalled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stalled
+}
+
+func userProto(user *model.User) *gen_proto.User {
+	return &gen_proto.User{
+		Id:           user.ID,
+		FullName:     user.FullName,
+		Email:        user.Email,
+		Role:         user.Role,
+		Locale:       user.Locale,
+		Organization: user.Organization,
+	}
+}
@@ -0,0 +1,86 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"tender-backend/custom_errors"
+	"tender-backend/internal/http/token"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type claimsKey struct{}
+
+// Policy says which methods, by full name, can be called without a token and
+// which only by users of one role. Every other method needs a valid token.
+type Policy struct {
+	Public map[string]bool
+	Roles  map[string]string
+}
+
+// Claims returns the claims of the token the call was authenticated with.
+func Claims(ctx context.Context) (*token.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*token.Claims)
+	return claims, ok
+}
+
+// UnaryAuth verifies the bearer token in the authorization metadata of unary
+// calls according to policy, and stores its claims in the context.
+func UnaryAuth(tokens *token.Manager, policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := policy.authorize(ctx, tokens, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is UnaryAuth for streaming calls.
+func StreamAuth(tokens *token.Manager, policy Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := policy.authorize(ss.Context(), tokens, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (p Policy) authorize(ctx context.Context, tokens *token.Manager, method string) (context.Context, error) {
+	if p.Public[method] {
+		return ctx, nil
+	}
+
+	var header string
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		header = values[0]
+	}
+	if header == "" {
+		return nil, custom_errors.NewUnauthorizedError("Missing token")
+	}
+
+	// Accept the token with or without the Bearer prefix, like the REST API
+	tokenStr, _ := strings.CutPrefix(header, "Bearer ")
+	claims, err := tokens.VerifyJWT(tokenStr)
+	if err != nil {
+		return nil, custom_errors.NewUnauthorizedError("Invalid or expired token").Wrap(err)
+	}
+
+	if role, ok := p.Roles[method]; ok && claims.Role != role {
+		return nil, custom_errors.NewForbiddenError("Only " + role + "s can call this method")
+	}
+
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
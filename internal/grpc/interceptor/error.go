@@ -0,0 +1,138 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"tender-backend/custom_errors"
+	"tender-backend/logging"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the domain of the ErrorInfo detail of every error.
+const errorDomain = "tender-backend"
+
+// UnaryErrors turns the errors of later interceptors and handlers into gRPC
+// statuses, the counterpart of the REST ErrorMiddleware. The status carries
+// the error code and request ID in an ErrorInfo detail, and invalid fields
+// in a BadRequest detail. Errors that are not custom_errors.AppError are
+// answered as internal errors, and the causes of server errors are logged.
+func UnaryErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		res, err := handler(ctx, req)
+		return res, toStatus(ctx, err)
+	}
+}
+
+// StreamErrors is UnaryErrors for streaming calls.
+func StreamErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toStatus(ss.Context(), handler(srv, ss))
+	}
+}
+
+// UnaryRecovery answers panics of later handlers with an internal error.
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = panicError(recovered)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery is UnaryRecovery for streaming calls.
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = panicError(recovered)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func panicError(recovered any) error {
+	return custom_errors.NewGenericError("Internal server error").Wrap(fmt.Errorf("panic: %v\n%s", recovered, debug.Stack()))
+}
+
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	appErr := custom_errors.From(err)
+	if appErr.StatusCode >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "Request failed", "code", appErr.Code, "error", appErr)
+	}
+
+	st := status.New(grpcCode(appErr), appErr.Message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   appErr.Code,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"request_id": logging.RequestID(ctx)},
+	}}
+	if len(appErr.Fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range appErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// grpcCode maps the HTTP status of an error to the closest gRPC code.
+func grpcCode(err *custom_errors.AppError) codes.Code {
+	switch err.Code {
+	case custom_errors.CodeTenderNotOpen, custom_errors.CodeInvalidStatusTransition:
+		return codes.FailedPrecondition
+	}
+
+	switch err.StatusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.Aborted
+	case http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
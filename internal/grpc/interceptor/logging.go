@@ -0,0 +1,88 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"tender-backend/logging"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata is the metadata key carrying the request ID, the gRPC
+// counterpart of the X-Request-ID header.
+var requestIDMetadata = strings.ToLower(logging.RequestIDHeader)
+
+// UnaryRequestID accepts the x-request-id metadata of the caller or generates
+// an ID, returns it in the response header and stores it in the context.
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// StreamRequestID is UnaryRequestID for streaming calls.
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDMetadata); len(values) > 0 {
+		id = values[0]
+	}
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+	return logging.WithRequestID(ctx, id)
+}
+
+// UnaryAccessLog logs every call once it completed, at error level for
+// server errors.
+func UnaryAccessLog() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// StreamAccessLog is UnaryAccessLog for streaming calls, logged when the
+// stream ends.
+func StreamAccessLog() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	}
+
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"duration", time.Since(start),
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+
+	slog.Log(ctx, level, "Call", attrs...)
+}
@@ -0,0 +1,133 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"tender-backend/custom_errors"
+	"tender-backend/internal/http/token"
+	"tender-backend/metrics"
+	"tender-backend/rate_limiter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// apiKeyMetadata is the counterpart of the X-API-Key header.
+const apiKeyMetadata = "x-api-key"
+
+// RateLimit applies the rate limit policies of the REST API to gRPC calls.
+type RateLimit struct {
+	Limiter  *rate_limiter.Limiter
+	Policies rate_limiter.Policies
+	Tokens   *token.Manager
+	// APIKeys are the known keys of API clients; other x-api-key values are ignored.
+	APIKeys []string
+	// Routes maps full method names to the REST route they mirror, e.g.
+	// "POST /login", so the policies of that route apply. Other methods are
+	// matched by their full name, which policies can name as a route.
+	Routes map[string]string
+}
+
+// UnaryRateLimit rejects unary calls over their quota with ResourceExhausted,
+// counting them by user or peer address like RateLimitMiddleware does by user
+// or IP. Like the REST middleware it runs before authentication and only
+// parses the token to pick the role and key. The quota is returned in the
+// x-ratelimit-* response headers.
+func UnaryRateLimit(limit RateLimit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		header, err := limit.check(ctx, info.FullMethod)
+		if header != nil {
+			_ = grpc.SetHeader(ctx, header)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit is UnaryRateLimit for streaming calls, counted when the stream opens.
+func StreamRateLimit(limit RateLimit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		header, err := limit.check(ss.Context(), info.FullMethod)
+		if header != nil {
+			_ = ss.SetHeader(header)
+		}
+		if err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (l RateLimit) check(ctx context.Context, fullMethod string) (metadata.MD, error) {
+	method, route := "", fullMethod
+	if mirrored, ok := l.Routes[fullMethod]; ok {
+		method, route, _ = strings.Cut(mirrored, " ")
+	}
+
+	role := rate_limiter.RoleAnonymous
+	subject := rate_limiter.Subject{
+		APIKey: rate_limiter.VerifyAPIKey(firstMetadata(ctx, apiKeyMetadata), l.APIKeys),
+		IP:     peerIP(ctx),
+	}
+	if tokenStr, _ := strings.CutPrefix(firstMetadata(ctx, "authorization"), "Bearer "); tokenStr != "" {
+		if claims, err := l.Tokens.VerifyJWT(tokenStr); err == nil {
+			role = claims.Role
+			subject.UserID = claims.UserID
+			subject.Organization = claims.Organization
+		}
+	}
+
+	result, rejected, err := l.Limiter.Check(ctx, l.Policies, method, route, role, subject)
+	if err != nil {
+		slog.WarnContext(ctx, "Rate limiter unavailable", "error", err)
+		if l.Limiter.FailOpen {
+			return nil, nil
+		}
+		return nil, custom_errors.NewServiceUnavailableError("Rate limiter unavailable").Wrap(err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	header := metadata.Pairs(
+		"x-ratelimit-limit", strconv.Itoa(result.Limit),
+		"x-ratelimit-remaining", strconv.Itoa(result.Remaining),
+		"x-ratelimit-reset", strconv.FormatInt(result.Reset.Unix(), 10),
+	)
+	if rejected != nil {
+		metrics.RateLimitRejections.WithLabelValues(rejected.Name).Inc()
+		retryAfter := result.RetryAfter(time.Now())
+		header.Set("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return header, custom_errors.NewTooManyRequestsError("Too many requests, rate limit policy " + rejected.Name + " exceeded")
+	}
+	return header, nil
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerIP returns the IP of the caller's connection. Unlike REST, no proxy
+// headers are trusted.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -1,11 +1,9 @@
 package handlers
 
 import (
-	"fmt"
-	"gorm.io/gorm/utils"
+	"log/slog"
 	"net/http"
-	"tender-backend/config"
-	"tender-backend/internal/http/token"
+	"tender-backend/custom_errors"
 	request_model "tender-backend/model/request"
 	response_model "tender-backend/model/response"
 
@@ -20,52 +18,27 @@ import (
 // @Produce json
 // @Param user body request_model.CreateUserReq true "User registration request"
 // @Success 201 {object} response_model.LoginRes "JWT tokens"
-// @Failure 400 {object} string "Invalid request payload"
-// @Failure 500 {object} string "Server error"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Router /register [post]
 func (h *HTTPHandler) Register(c *gin.Context) {
 	var req request_model.CreateUserReq
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"Invalid request payload": err.Error()})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	if req.Email == "" || req.Username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "username or email cannot be empty"})
+	user, err2 := h.UserService.CreateUser(c.Request.Context(), &req)
+	if err2 != nil {
+		slog.WarnContext(c.Request.Context(), "Failed to create user", "error", err2)
+		c.Error(err2)
 		return
 	}
 
-	if !config.IsValidEmail(req.Email) {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid email format"})
-		return
-	}
-
-	availableRoles := []string{"client", "contractor"}
-
-	if !utils.Contains(availableRoles, req.Role) {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid role"})
-		return
-	}
-
-	hashedPassword, err := config.HashPassword(req.Password)
-	if err != nil {
-		fmt.Printf("Error hashing password: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Server error", "err": err.Error()})
-		return
-	}
-
-	req.Password = hashedPassword
-	user, err := h.UserService.CreateUser(&req)
-	if err != nil {
-		fmt.Printf("Error creating user: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
-	}
-
-	tkn, err := token.GenerateJWT(user.ID, user.Role)
+	tkn, err := h.Tokens.GenerateJWT(user.ID, user.Role, user.Organization)
 
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.Error(custom_errors.NewAppError(err))
 		return
 	}
 
@@ -85,37 +58,26 @@ func (h *HTTPHandler) Register(c *gin.Context) {
 // @Produce json
 // @Param credentials body request_model.LoginUserReq true "User login credentials"
 // @Success 200 {object} response_model.LoginRes "JWT tokens"
-// @Failure 400 {object} string "Invalid request payload"
-// @Failure 401 {object} string "Invalid email or password"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Invalid email or password"
 // @Router /login [post]
 func (h *HTTPHandler) Login(c *gin.Context) {
 	req := request_model.LoginUserReq{}
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"Invalid request payload": err.Error()})
-		return
-	}
-
-	if req.Username == "" || req.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Username and password are required"})
-		return
-	}
-
-	user, err := h.UserService.GetByUsername(req.Username)
-	if err != nil {
-		fmt.Printf("Error fetching user: %v", err)
-		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	if !config.CheckPasswordHash(req.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid username or password"})
+	user, err2 := h.UserService.Authenticate(c.Request.Context(), &req)
+	if err2 != nil {
+		c.Error(err2)
 		return
 	}
 
-	tkn, err := token.GenerateJWT(user.ID, user.Role)
+	tkn, err := h.Tokens.GenerateJWT(user.ID, user.Role, user.Organization)
 
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.Error(custom_errors.NewAppError(err))
 		return
 	}
 
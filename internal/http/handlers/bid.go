@@ -3,7 +3,10 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
 	request_model "tender-backend/model/request"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,9 +20,9 @@ import (
 // @Param tender_id path string true "Tender ID"
 // @Param bid body request_model.CreateBidReq true "Bid creation request"
 // @Success 201 {object} model.Bid "Bid created successfully"
-// @Failure 400 {object} string "Invalid request payload"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 500 {object} string "Server error"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /api/contractor/tenders/{tender_id}/bid [POST]
 func (h *HTTPHandler) CreateBid(c *gin.Context) {
@@ -27,20 +30,20 @@ func (h *HTTPHandler) CreateBid(c *gin.Context) {
 	tenderIdStr := c.Param("tender_id")
 	tenderId, err := strconv.Atoi(tenderIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
 	contractorId := c.GetInt64("user_id")
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		c.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	createdBid, err2 := h.BidService.CreateBid(&req, int64(tenderId), contractorId)
+	createdBid, err2 := h.BidService.CreateBid(c.Request.Context(), &req, int64(tenderId), contractorId)
 	if err2 != nil {
-		c.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		c.Error(err2)
 		return
 	}
 
@@ -55,33 +58,36 @@ func (h *HTTPHandler) CreateBid(c *gin.Context) {
 // @Produce json
 // @Param tender_id path string true "Tender ID"
 // @Param bid_id path string true "Bid ID"
+// @Param If-None-Match header string false "ETag of the cached bid"
+// @Param If-Modified-Since header string false "Last-Modified of the cached bid"
 // @Success 200 {object} model.Bid "Bid retrieved successfully"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 404 {object} string "Bid not found"
-// @Failure 500 {object} string "Server error"
+// @Success 304 "Bid not modified"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Bid not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /api/contractor/tenders/{tender_id}/bid/{bid_id} [GET]
 func (h *HTTPHandler) GetBid(c *gin.Context) {
 	bidIDStr := c.Param("bid_id")
 	bidID, err := strconv.Atoi(bidIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bid ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid bid ID"))
 		return
 	}
 	tenderIDStr := c.Param("tender_id")
 	tenderID, err := strconv.Atoi(tenderIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
-	bid, err := h.BidService.GetBidByID(int64(bidID), int64(tenderID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bid"})
+	bid, err2 := h.BidService.GetBidByID(c.Request.Context(), int64(bidID), int64(tenderID))
+	if err2 != nil {
+		c.Error(err2)
 		return
 	}
 
-	c.JSON(http.StatusOK, bid)
+	respondConditional(c, entityTag("bid", bid.ID, bid.Version), bid.UpdatedAt, bid)
 }
 
 // GetBids godoc
@@ -91,26 +97,28 @@ func (h *HTTPHandler) GetBid(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param tender_id path string true "Tender ID"
+// @Param If-None-Match header string false "ETag of the cached list"
 // @Success 200 {object} []model.Bid "All bids retrieved successfully"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 500 {object} string "Server error"
+// @Success 304 "Bids not modified"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /api/client/contractor/tenders/{tender_id}/bids [get]
 func (h *HTTPHandler) GetBids(c *gin.Context) {
 	tenderIDStr := c.Param("tender_id")
 	tenderID, err := strconv.Atoi(tenderIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
-	bids, err2 := h.BidService.GetAllBids(int64(tenderID))
+	bids, err2 := h.BidService.GetAllBids(c.Request.Context(), int64(tenderID))
 	if err2 != nil {
-		c.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		c.Error(err2)
 		return
 	}
 
-	c.JSON(http.StatusOK, bids)
+	respondConditional(c, bidsTag(bids), time.Time{}, bids)
 }
 
 // GetContractorBids godoc
@@ -119,20 +127,22 @@ func (h *HTTPHandler) GetBids(c *gin.Context) {
 // @Tags Bid
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag of the cached list"
 // @Success 200 {object} []model.Bid "All bids retrieved successfully"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 500 {object} string "Server error"
+// @Success 304 "Bids not modified"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /api/contractor/bids [get]
 func (h *HTTPHandler) GetContractorBids(c *gin.Context) {
 	contractorID := c.GetInt64("user_id")
-	bids, err := h.BidService.GetContractorBids(contractorID)
+	bids, err := h.BidService.GetContractorBids(c.Request.Context(), contractorID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve bids"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, bids)
+	respondConditional(c, bidsTag(bids), time.Time{}, bids)
 }
 
 // DeleteBid godoc
@@ -142,25 +152,43 @@ func (h *HTTPHandler) GetContractorBids(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param bid_id path string true "Bid ID"
+// @Param If-Match header string true "ETag of the bid being deleted"
 // @Success 200 {object} string "Bid deleted successfully"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 404 {object} string "Bid not found"
-// @Failure 500 {object} string "Server error"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Bid not found"
+// @Failure 412 {object} response_model.ProblemRes "Bid was modified"
+// @Failure 428 {object} response_model.ProblemRes "If-Match header is required"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /api/contractor/bids/{bid_id} [DELETE]
 func (h *HTTPHandler) DeleteBid(c *gin.Context) {
 	bidIDStr := c.Param("bid_id")
 	bidID, err := strconv.Atoi(bidIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bid ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid bid ID"))
+		return
+	}
+
+	versions, ok := ifMatchVersions(c, "bid", int64(bidID))
+	if !ok {
 		return
 	}
 
-	err2 := h.BidService.DeleteBid(int64(bidID), c.GetInt64("user_id"))
+	err2 := h.BidService.DeleteBid(c.Request.Context(), int64(bidID), c.GetInt64("user_id"), versions)
 	if err2 != nil {
-		c.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		c.Error(err2)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Bid deleted successfully"})
 }
+
+// bidsTag returns the list ETag of the bids.
+func bidsTag(bids []model.Bid) string {
+	ids := make([]int64, len(bids))
+	versions := make([]int64, len(bids))
+	for i := range bids {
+		ids[i], versions[i] = bids[i].ID, bids[i].Version
+	}
+	return listTag("bid", ids, versions)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-backend/custom_errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// entityTag is the strong ETag of one version of a tender or bid.
+func entityTag(kind string, id, version int64) string {
+	return fmt.Sprintf(`"%s-%d-v%d"`, kind, id, version)
+}
+
+// listTag is the strong ETag of a list of tenders or bids, a hash of the ID
+// and version of each item in order. It changes whenever an item is added,
+// updated or deleted, which the newest update time of the items would not
+// show for deletes.
+func listTag(kind string, ids, versions []int64) string {
+	hash := sha256.New()
+	for i := range ids {
+		fmt.Fprintf(hash, "%d-v%d,", ids[i], versions[i])
+	}
+	return fmt.Sprintf(`"%ss-%s"`, kind, hex.EncodeToString(hash.Sum(nil)[:16]))
+}
+
+// respondConditional writes body as JSON with its ETag and Last-Modified, or
+// 304 Not Modified when the client's copy is current. Lists pass a zero
+// lastModified, so they are only validated by their ETag.
+func respondConditional(c *gin.Context, etag string, lastModified time.Time, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.Error(custom_errors.NewAppError(err))
+		return
+	}
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when the client
+// sent no ETag, as RFC 9110 requires.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err == nil {
+			// HTTP dates have a precision of one second.
+			return !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+
+	return false
+}
+
+// ifMatchVersions reads the versions of the resource the client sent in the
+// required If-Match header. "*" returns no versions, which match any version.
+// It responds 428 when the header is missing and 412 when it names no version
+// of this resource, and then returns false.
+func ifMatchVersions(c *gin.Context, kind string, id int64) ([]int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.Error(custom_errors.NewPreconditionRequiredError("If-Match header is required"))
+		return nil, false
+	}
+
+	prefix := fmt.Sprintf(`"%s-%d-v`, kind, id)
+	var versions []int64
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return nil, true
+		}
+
+		// If-Match uses strong comparison, so weak tags never match.
+		if !strings.HasPrefix(candidate, prefix) || !strings.HasSuffix(candidate, `"`) {
+			continue
+		}
+
+		version, err := strconv.ParseInt(candidate[len(prefix):len(candidate)-1], 10, 64)
+		if err == nil {
+			versions = append(versions, version)
+		}
+	}
+
+	if len(versions) == 0 {
+		c.Error(custom_errors.NewPreconditionFailedError("If-Match does not match the current version"))
+		return nil, false
+	}
+
+	return versions, true
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestContext(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	return c, recorder
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		name         string
+		ifMatch      string
+		wantVersions []int64
+		wantStatus   int
+	}{
+		{name: "missing", ifMatch: "", wantStatus: http.StatusPreconditionRequired},
+		{name: "current version", ifMatch: `"tender-7-v3"`, wantVersions: []int64{3}},
+		{name: "several versions", ifMatch: `"tender-7-v3", "tender-7-v4"`, wantVersions: []int64{3, 4}},
+		{name: "any version", ifMatch: "*", wantVersions: nil},
+		{name: "other tender", ifMatch: `"tender-8-v3"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "other kind", ifMatch: `"bid-7-v3"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "weak tag", ifMatch: `W/"tender-7-v3"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "malformed version", ifMatch: `"tender-7-vx"`, wantStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(map[string]string{"If-Match": tt.ifMatch})
+
+			versions, ok := ifMatchVersions(c, "tender", 7)
+
+			if tt.wantStatus != 0 {
+				if ok || len(c.Errors) == 0 {
+					t.Fatalf("got versions %v, want status %d", versions, tt.wantStatus)
+				}
+				if status := custom_errors.From(c.Errors.Last().Err).StatusCode; status != tt.wantStatus {
+					t.Errorf("status = %d, want %d", status, tt.wantStatus)
+				}
+				return
+			}
+			if !ok || !reflect.DeepEqual(versions, tt.wantVersions) {
+				t.Errorf("versions = %v, %v, want %v", versions, ok, tt.wantVersions)
+			}
+		})
+	}
+}
+
+func TestRespondConditional(t *testing.T) {
+	lastModified := time.Date(2024, 6, 1, 12, 0, 0, 500_000_000, time.UTC)
+	etag := entityTag("tender", 7, 3)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		lastModified time.Time
+		wantStatus   int
+	}{
+		{name: "unconditional", wantStatus: http.StatusOK},
+		{name: "matching tag", headers: map[string]string{"If-None-Match": etag}, wantStatus: http.StatusNotModified},
+		{name: "weak matching tag", headers: map[string]string{"If-None-Match": `W/"tender-7-v3"`}, wantStatus: http.StatusNotModified},
+		{name: "one of several tags", headers: map[string]string{"If-None-Match": `"tender-7-v2", "tender-7-v3"`}, wantStatus: http.StatusNotModified},
+		{name: "any tag", headers: map[string]string{"If-None-Match": "*"}, wantStatus: http.StatusNotModified},
+		{name: "old tag", headers: map[string]string{"If-None-Match": `"tender-7-v2"`}, wantStatus: http.StatusOK},
+		{
+			name:         "not modified since",
+			headers:      map[string]string{"If-Modified-Since": "Sat, 01 Jun 2024 12:00:00 GMT"},
+			lastModified: lastModified,
+			wantStatus:   http.StatusNotModified,
+		},
+		{
+			name:         "modified since",
+			headers:      map[string]string{"If-Modified-Since": "Sat, 01 Jun 2024 11:59:59 GMT"},
+			lastModified: lastModified,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "old tag wins over date",
+			headers:      map[string]string{"If-None-Match": `"tender-7-v2"`, "If-Modified-Since": "Sat, 01 Jun 2024 12:00:00 GMT"},
+			lastModified: lastModified,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:       "date without last modified",
+			headers:    map[string]string{"If-Modified-Since": "Sat, 01 Jun 2024 12:00:00 GMT"},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newTestContext(tt.headers)
+
+			respondConditional(c, etag, tt.lastModified, gin.H{"id": 7})
+			c.Writer.WriteHeaderNow()
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %s, want %s", got, etag)
+			}
+			if tt.wantStatus == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("304 has a body: %s", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestTendersTag(t *testing.T) {
+	tenders := []model.Tender{{ID: 1, Version: 1}, {ID: 2, Version: 4}, {ID: 3, Version: 2}}
+	base := tendersTag(tenders)
+
+	tests := []struct {
+		name    string
+		tenders []model.Tender
+	}{
+		{name: "updated", tenders: []model.Tender{{ID: 1, Version: 1}, {ID: 2, Version: 5}, {ID: 3, Version: 2}}},
+		{name: "deleted", tenders: []model.Tender{{ID: 1, Version: 1}, {ID: 3, Version: 2}}},
+		{name: "added", tenders: append(tenders[:3:3], model.Tender{ID: 4, Version: 1})},
+		{name: "reordered", tenders: []model.Tender{{ID: 2, Version: 4}, {ID: 1, Version: 1}, {ID: 3, Version: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tag := tendersTag(tt.tenders); tag == base {
+				t.Errorf("list tag did not change: %s", tag)
+			}
+		})
+	}
+
+	if tag := tendersTag([]model.Tender{{ID: 1, Version: 1}, {ID: 2, Version: 4}, {ID: 3, Version: 2}}); tag != base {
+		t.Errorf("list tag of the same tenders changed: %s, want %s", tag, base)
+	}
+	if bids := bidsTag([]model.Bid{{ID: 1, Version: 1}}); bids == tendersTag([]model.Tender{{ID: 1, Version: 1}}) {
+		t.Errorf("bid and tender lists share the tag %s", bids)
+	}
+}
@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"tender-backend/circuit_breaker"
+	"tender-backend/config"
+	"tender-backend/internal/http/token"
+	"tender-backend/repository"
 	"tender-backend/server"
 
 	"github.com/redis/go-redis/v9" // Use v9 Redis package
@@ -8,17 +12,35 @@ import (
 )
 
 type HTTPHandler struct {
-	UserService   *server.UserService
-	BidService    *server.BidService
-	TenderService *server.TenderService
-	RedisClient   *redis.Client // v9 Redis client
+	UserService         *server.UserService
+	BidService          *server.BidService
+	TenderService       *server.TenderService
+	NotificationService *server.NotificationService
+	WebhookService      *server.WebhookService
+	SavedSearchService  *server.SavedSearchService
+	RateLimitService    *server.RateLimitService
+	HealthService       *server.HealthService
+	Tokens              *token.Manager
+	RedisClient         *redis.Client // v9 Redis client
+	RedisBreaker        *circuit_breaker.Breaker
 }
 
-func NewHttpHandler(db *gorm.DB, RedisClient *redis.Client) *HTTPHandler {
+func NewHttpHandler(cfg *config.Config, db *gorm.DB, RedisClient *redis.Client, redisBreaker *circuit_breaker.Breaker, notificationService *server.NotificationService, rateLimitService *server.RateLimitService, healthService *server.HealthService) *HTTPHandler {
+	store := repository.NewGorm(db)
+	savedSearchService := server.NewSavedSearchService(store, notificationService)
+	tenderService := server.NewTenderService(store, RedisClient, cfg.Cache, notificationService, savedSearchService)
+
 	return &HTTPHandler{
-		UserService:   server.NewUserService(db),
-		BidService:    server.NewBidService(db, RedisClient),
-		TenderService: server.NewTenderService(db, RedisClient),
-		RedisClient:   RedisClient,
+		UserService:         server.NewUserService(store),
+		BidService:          server.NewBidService(store, tenderService),
+		TenderService:       tenderService,
+		NotificationService: notificationService,
+		WebhookService:      server.NewWebhookService(store),
+		SavedSearchService:  savedSearchService,
+		RateLimitService:    rateLimitService,
+		HealthService:       healthService,
+		Tokens:              token.NewManager(cfg.Auth.JWTSecret, cfg.Auth.TokenLifetime),
+		RedisClient:         RedisClient,
+		RedisBreaker:        redisBreaker,
 	}
 }
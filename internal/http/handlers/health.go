@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"tender-backend/circuit_breaker"
+	response_model "tender-backend/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health godoc
+// @Summary Service health
+// @Description Reports the Redis circuit breaker and the cache. The service keeps serving from PostgreSQL while Redis is down, so the status is then "degraded" rather than an error.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response_model.HealthRes
+// @Router /health [get]
+func (h *HTTPHandler) Health(c *gin.Context) {
+	res := response_model.HealthRes{
+		Status: "ok",
+		Redis:  h.RedisBreaker.Stats(),
+		Cache:  h.TenderService.CacheStats(),
+	}
+	if h.RedisBreaker.State() != circuit_breaker.Closed {
+		res.Status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Answers as long as the process serves HTTP. Dependencies are not checked, restarting the service would not bring them back.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HTTPHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Checks PostgreSQL, Redis and the message broker. Answers 503 while PostgreSQL or the broker is unavailable and while the service shuts down; Redis being down only degrades the service.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response_model.ReadinessRes
+// @Failure 503 {object} response_model.ReadinessRes
+// @Router /readyz [get]
+func (h *HTTPHandler) Readiness(c *gin.Context) {
+	res, ready := h.HealthService.Readiness(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, res)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"tender-backend/custom_errors"
+	request_model "tender-backend/model/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Retrieves the notifications of the authenticated user, rendered in the given locale or the user's locale.
+// @Tags Notification
+// @Produce json
+// @Param locale query string false "Locale (en, ru, uz)"
+// @Success 200 {object} []model.Notification "Notifications retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /notifications [GET]
+func (h *HTTPHandler) GetNotifications(c *gin.Context) {
+	notifications, err := h.NotificationService.GetNotifications(c.Request.Context(), c.GetInt64("user_id"), c.Query("locale"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Retrieves the offline channels chosen for each event type.
+// @Tags Notification
+// @Produce json
+// @Success 200 {object} []model.NotificationPreference "Preferences retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /users/notification-preferences [GET]
+func (h *HTTPHandler) GetNotificationPreferences(c *gin.Context) {
+	preferences, err := h.NotificationService.GetPreferences(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// SetNotificationPreference godoc
+// @Summary Set a notification preference
+// @Description Sets the offline channels (email, sms, webhook) for an event type.
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param preference body request_model.SetNotificationPreferenceReq true "Notification preference"
+// @Success 200 {object} model.NotificationPreference "Preference saved successfully"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /users/notification-preferences [PUT]
+func (h *HTTPHandler) SetNotificationPreference(c *gin.Context) {
+	var req request_model.SetNotificationPreferenceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
+		return
+	}
+
+	preference, err := h.NotificationService.SetPreference(c.Request.Context(), c.GetInt64("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// GetNotificationSettings godoc
+// @Summary Get notification settings
+// @Description Retrieves the phone number, webhook URL and quiet hours used for offline notifications.
+// @Tags Notification
+// @Produce json
+// @Success 200 {object} model.NotificationSettings "Settings retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /users/notification-settings [GET]
+func (h *HTTPHandler) GetNotificationSettings(c *gin.Context) {
+	settings, err := h.NotificationService.GetSettings(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateNotificationSettings godoc
+// @Summary Update notification settings
+// @Description Updates the phone number, webhook URL and quiet hours (HH:MM in time_zone, UTC by default) used for offline notifications. A new webhook URL gets a new webhook_secret, which signs the notifications sent to it.
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param settings body request_model.UpdateNotificationSettingsReq true "Notification settings"
+// @Success 200 {object} model.NotificationSettings "Settings updated successfully"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /users/notification-settings [PUT]
+func (h *HTTPHandler) UpdateNotificationSettings(c *gin.Context) {
+	var req request_model.UpdateNotificationSettingsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
+		return
+	}
+
+	settings, err := h.NotificationService.UpdateSettings(c.Request.Context(), c.GetInt64("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRateLimitPolicies godoc
+// @Summary Get rate limit policies
+// @Description Lists the configured rate limit policies.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {object} []rate_limiter.Policy "Policies retrieved successfully"
+// @Failure 403 {object} response_model.ProblemRes "Forbidden"
+// @Router /api/admin/rate-limits/policies [GET]
+func (h *HTTPHandler) GetRateLimitPolicies(c *gin.Context) {
+	c.JSON(http.StatusOK, h.RateLimitService.GetPolicies())
+}
+
+// GetRateLimitUsage godoc
+// @Summary Get rate limit usage
+// @Description Shows the current window of every active rate limit key, optionally of one policy.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param policy query string false "Policy name"
+// @Success 200 {object} []response_model.RateLimitUsageRes "Usage retrieved successfully"
+// @Failure 403 {object} response_model.ProblemRes "Forbidden"
+// @Failure 404 {object} response_model.ProblemRes "Policy not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Router /api/admin/rate-limits [GET]
+func (h *HTTPHandler) GetRateLimitUsage(c *gin.Context) {
+	usage, err := h.RateLimitService.GetUsage(c.Query("policy"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// ResetRateLimit godoc
+// @Summary Reset a rate limit key
+// @Description Clears the window of a key such as "bid-submission:user:42", restoring its full quota.
+// @Tags Admin
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param key query string true "Rate limit key"
+// @Success 200 {object} string "Rate limit reset successfully"
+// @Failure 400 {object} response_model.ProblemRes "Key is required"
+// @Failure 403 {object} response_model.ProblemRes "Forbidden"
+// @Failure 404 {object} response_model.ProblemRes "Key not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Router /api/admin/rate-limits [DELETE]
+func (h *HTTPHandler) ResetRateLimit(c *gin.Context) {
+	if err := h.RateLimitService.ResetKey(c.Query("key")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rate limit reset successfully"})
+}
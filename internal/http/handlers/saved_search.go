@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tender-backend/custom_errors"
+	request_model "tender-backend/model/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSavedSearch godoc
+// @Summary Save a tender search
+// @Description Saves search criteria. New tenders matching them trigger an alert and, optionally, a daily or weekly digest.
+// @Tags SavedSearch
+// @Accept json
+// @Produce json
+// @Param search body request_model.CreateSavedSearchReq true "Saved search"
+// @Success 201 {object} model.SavedSearch "Saved search created successfully"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/contractor/saved-searches [POST]
+func (h *HTTPHandler) CreateSavedSearch(c *gin.Context) {
+	var req request_model.CreateSavedSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
+		return
+	}
+
+	search, err := h.SavedSearchService.CreateSavedSearch(c.Request.Context(), &req, c.GetInt64("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// GetSavedSearches godoc
+// @Summary Get saved searches
+// @Description Retrieves the saved searches of the authenticated contractor.
+// @Tags SavedSearch
+// @Produce json
+// @Success 200 {object} []model.SavedSearch "Saved searches retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/contractor/saved-searches [GET]
+func (h *HTTPHandler) GetSavedSearches(c *gin.Context) {
+	searches, err := h.SavedSearchService.GetSavedSearches(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, searches)
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete a saved search
+// @Description Deletes a saved search by its ID.
+// @Tags SavedSearch
+// @Param search_id path int true "Saved search ID"
+// @Success 200 {object} string "Saved search deleted successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Saved search not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/contractor/saved-searches/{search_id} [DELETE]
+func (h *HTTPHandler) DeleteSavedSearch(c *gin.Context) {
+	searchID, err := strconv.Atoi(c.Param("search_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid saved search ID"))
+		return
+	}
+
+	err2 := h.SavedSearchService.DeleteSavedSearch(c.Request.Context(), int64(searchID), c.GetInt64("user_id"))
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
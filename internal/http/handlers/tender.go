@@ -2,9 +2,10 @@ package handlers
 
 import (
 	"strconv"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
 	request_model "tender-backend/model/request"
-
-	"gorm.io/gorm/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,14 +24,14 @@ func (h *HTTPHandler) CreateTender(ctx *gin.Context) {
 	req := request_model.CreateTenderReq{}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(400, gin.H{"message": "Invalid input"})
+		ctx.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	res, err2 := h.TenderService.CreateTender(&req, ctx.GetInt64("user_id"))
+	res, err2 := h.TenderService.CreateTender(ctx.Request.Context(), &req, ctx.GetInt64("user_id"))
 
 	if err2 != nil {
-		ctx.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		ctx.Error(err2)
 		return
 	}
 
@@ -44,22 +45,25 @@ func (h *HTTPHandler) CreateTender(ctx *gin.Context) {
 // @Tags Tender
 // @Produce json
 // @Param tender_id path int true "Tender ID"
+// @Param If-None-Match header string false "ETag of the cached tender"
+// @Param If-Modified-Since header string false "Last-Modified of the cached tender"
 // @Success 200 {object} model.Tender
+// @Success 304 "Tender not modified"
 // @Router /api/client/tenders/{tender_id} [get]
 func (h *HTTPHandler) GetTender(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("tender_id"))
 	if err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid tender ID"})
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
-	res, err := h.TenderService.GetTenderById(int64(id))
-	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+	res, err2 := h.TenderService.GetTenderById(ctx.Request.Context(), int64(id))
+	if err2 != nil {
+		ctx.Error(err2)
 		return
 	}
 
-	ctx.JSON(200, res)
+	respondConditional(ctx, entityTag("tender", res.ID, res.Version), res.UpdatedAt, res)
 }
 
 // GetTenders godoc
@@ -68,17 +72,19 @@ func (h *HTTPHandler) GetTender(ctx *gin.Context) {
 // @Description Get all tenders
 // @Tags Tender
 // @Produce json
+// @Param If-None-Match header string false "ETag of the cached list"
 // @Success 200 {object} []model.Tender
+// @Success 304 "Tenders not modified"
 // @Router /api/client/tenders [get]
 func (h *HTTPHandler) GetTenders(ctx *gin.Context) {
-	res, err := h.TenderService.GetTenders()
+	res, err := h.TenderService.GetTenders(ctx.Request.Context())
 
 	if err != nil {
-		ctx.JSON(500, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(200, res)
+	respondConditional(ctx, tendersTag(res), time.Time{}, res)
 }
 
 // UpdateTender godoc
@@ -89,14 +95,17 @@ func (h *HTTPHandler) GetTenders(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param tender_id path int true "Tender ID"
+// @Param If-Match header string true "ETag of the tender being updated"
 // @Param tender body request_model.UpdateTenderReq true "Tender information"
 // @Success 200 {object} model.Tender
+// @Failure 412 {object} response_model.ProblemRes "Tender was modified"
+// @Failure 428 {object} response_model.ProblemRes "If-Match header is required"
 // @Router /api/client/tenders/{tender_id} [put]
 func (h *HTTPHandler) UpdateTender(ctx *gin.Context) {
 	// Get tender ID from the path
 	tenderID, err := strconv.Atoi(ctx.Param("tender_id"))
 	if err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid tender ID"})
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
@@ -105,24 +114,24 @@ func (h *HTTPHandler) UpdateTender(ctx *gin.Context) {
 	// Bind the request JSON to the UpdateTenderReq struct
 	req := request_model.UpdateTenderReq{}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(400, gin.H{"error": err.Error()})
+		ctx.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	availableStatus := []string{"open", "closed", "awarded"}
-	if !utils.Contains(availableStatus, req.Status) {
-		ctx.JSON(400, gin.H{"message": "Invalid tender status"})
+	versions, ok := ifMatchVersions(ctx, "tender", int64(tenderID))
+	if !ok {
 		return
 	}
 
 	// Call the service method
-	_, err2 := h.TenderService.UpdateTender(int64(tenderID), clientID, &req)
+	tender, err2 := h.TenderService.UpdateTender(ctx.Request.Context(), int64(tenderID), clientID, &req, versions)
 	if err2 != nil {
-		ctx.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		ctx.Error(err2)
 		return
 	}
 
-	// Respond with the updated tender
+	// Respond with the ETag of the new version for the next update
+	ctx.Header("ETag", entityTag("tender", tender.ID, tender.Version))
 	ctx.JSON(200, gin.H{"message": "Tender status updated"})
 }
 
@@ -132,20 +141,28 @@ func (h *HTTPHandler) UpdateTender(ctx *gin.Context) {
 // @Description Delete a tender by ID
 // @Tags Tender
 // @Param tender_id path int true "Tender ID"
+// @Param If-Match header string true "ETag of the tender being deleted"
 // @Success 204
+// @Failure 412 {object} response_model.ProblemRes "Tender was modified"
+// @Failure 428 {object} response_model.ProblemRes "If-Match header is required"
 // @Router /api/client/tenders/{tender_id} [delete]
 func (h *HTTPHandler) DeleteTender(ctx *gin.Context) {
 	// Get tender ID from the path
 	tenderID, err := strconv.Atoi(ctx.Param("tender_id"))
 	if err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid tender ID"})
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
+		return
+	}
+
+	versions, ok := ifMatchVersions(ctx, "tender", int64(tenderID))
+	if !ok {
 		return
 	}
 
 	clientID := ctx.GetInt64("user_id")
-	err2 := h.TenderService.DeleteTender(int64(tenderID), clientID)
+	err2 := h.TenderService.DeleteTender(ctx.Request.Context(), int64(tenderID), clientID, versions)
 	if err2 != nil {
-		ctx.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		ctx.Error(err2)
 		return
 	}
 
@@ -163,27 +180,111 @@ func (h *HTTPHandler) DeleteTender(ctx *gin.Context) {
 // @Param tender_id path int true "Tender ID"
 // @Param bid_id path int true "Bid ID"
 // @Success 200 {object} model.Tender
+// @Failure 400 {object} response_model.ProblemRes "Tender is not open"
+// @Failure 404 {object} response_model.ProblemRes "Tender or bid not found"
+// @Failure 412 {object} response_model.ProblemRes "Tender was modified"
 // @Router /api/client/tenders/{tender_id}/award/{bid_id} [post]
 func (h *HTTPHandler) AwardTender(ctx *gin.Context) {
 	tenderID, err := strconv.Atoi(ctx.Param("tender_id"))
 	if err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid tender ID"})
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
 		return
 	}
 
 	bidID, err := strconv.Atoi(ctx.Param("bid_id"))
 	if err != nil {
-		ctx.JSON(400, gin.H{"error": "Invalid bid ID"})
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid bid ID"))
 		return
 	}
 
 	clientID := ctx.GetInt64("user_id")
 
-	err2 := h.TenderService.AwardTender(int64(tenderID), clientID, int64(bidID))
+	err2 := h.TenderService.AwardTender(ctx.Request.Context(), int64(tenderID), clientID, int64(bidID))
 	if err2 != nil {
-		ctx.JSON(err2.StatusCode, gin.H{"message": err2.Error()})
+		ctx.Error(err2)
 		return
 	}
 
 	ctx.JSON(200, gin.H{"message": "Bid awarded successfully"})
 }
+
+// WatchTender godoc
+// @Security BearerAuth
+// @Summary Watch a tender
+// @Description Subscribes the contractor to the deadline reminders of an open tender without bidding on it
+// @Tags Tender
+// @Produce json
+// @Param tender_id path int true "Tender ID"
+// @Success 200 {object} string "Tender watched"
+// @Failure 400 {object} response_model.ProblemRes "Tender is not open"
+// @Failure 404 {object} response_model.ProblemRes "Tender not found"
+// @Router /api/contractor/tenders/{tender_id}/watch [post]
+func (h *HTTPHandler) WatchTender(ctx *gin.Context) {
+	tenderID, err := strconv.Atoi(ctx.Param("tender_id"))
+	if err != nil {
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
+		return
+	}
+
+	err2 := h.TenderService.WatchTender(ctx.Request.Context(), int64(tenderID), ctx.GetInt64("user_id"))
+	if err2 != nil {
+		ctx.Error(err2)
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Tender watched"})
+}
+
+// UnwatchTender godoc
+// @Security BearerAuth
+// @Summary Stop watching a tender
+// @Description Unsubscribes the contractor from the deadline reminders of a tender
+// @Tags Tender
+// @Produce json
+// @Param tender_id path int true "Tender ID"
+// @Success 200 {object} string "Tender unwatched"
+// @Failure 404 {object} response_model.ProblemRes "Tender is not watched"
+// @Router /api/contractor/tenders/{tender_id}/watch [delete]
+func (h *HTTPHandler) UnwatchTender(ctx *gin.Context) {
+	tenderID, err := strconv.Atoi(ctx.Param("tender_id"))
+	if err != nil {
+		ctx.Error(custom_errors.NewInvalidParameterError("Invalid tender ID"))
+		return
+	}
+
+	err2 := h.TenderService.UnwatchTender(ctx.Request.Context(), int64(tenderID), ctx.GetInt64("user_id"))
+	if err2 != nil {
+		ctx.Error(err2)
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Tender unwatched"})
+}
+
+// GetWatchedTenders godoc
+// @Security BearerAuth
+// @Summary Get watched tenders
+// @Description Retrieves the tenders the contractor watches, closest deadline first
+// @Tags Tender
+// @Produce json
+// @Success 200 {object} []model.Tender
+// @Router /api/contractor/watched-tenders [get]
+func (h *HTTPHandler) GetWatchedTenders(ctx *gin.Context) {
+	tenders, err := h.TenderService.GetWatchedTenders(ctx.Request.Context(), ctx.GetInt64("user_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(200, tenders)
+}
+
+// tendersTag returns the list ETag of the tenders.
+func tendersTag(tenders []model.Tender) string {
+	ids := make([]int64, len(tenders))
+	versions := make([]int64, len(tenders))
+	for i := range tenders {
+		ids[i], versions[i] = tenders[i].ID, tenders[i].Version
+	}
+	return listTag("tender", ids, versions)
+}
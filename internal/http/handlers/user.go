@@ -3,7 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"tender-backend/config"
+	"tender-backend/custom_errors"
 	request_model "tender-backend/model/request"
 	response_model "tender-backend/model/response"
 
@@ -18,29 +18,31 @@ import (
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Success 200 {object} response_model.ProfileRes "User retrieved successfully"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 404 {object} string "User not found"
-// @Failure 500 {object} string "Server error"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "User not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /users/{user_id} [GET]
 func (h *HTTPHandler) GetUserByID(c *gin.Context) {
 	idStr := c.Param("user_id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(custom_errors.NewInvalidParameterError("Invalid user ID"))
 		return
 	}
 
-	user, err := h.UserService.GetUserByID(int64(id))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user", "details": err.Error()})
+	user, err2 := h.UserService.GetUserByID(c.Request.Context(), int64(id))
+	if err2 != nil {
+		c.Error(err2)
 		return
 	}
 	userRes := &response_model.ProfileRes{
-		ID:       user.ID,
-		FullName: user.FullName,
-		Email:    user.Email,
-		Role:     user.Role,
+		ID:           user.ID,
+		FullName:     user.FullName,
+		Email:        user.Email,
+		Role:         user.Role,
+		Locale:       user.Locale,
+		Organization: user.Organization,
 	}
 	c.JSON(http.StatusOK, userRes)
 }
@@ -53,10 +55,10 @@ func (h *HTTPHandler) GetUserByID(c *gin.Context) {
 // @Produce json
 // @Param user body request_model.UpdateUserReq true "User update request"
 // @Success 200 {object} response_model.ProfileRes "User updated successfully"
-// @Failure 400 {object} string "Invalid request payload"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 404 {object} string "User not found"
-// @Failure 500 {object} string "Server error"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "User not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /users [PUT]
 func (h *HTTPHandler) UpdateUser(c *gin.Context) {
@@ -64,26 +66,23 @@ func (h *HTTPHandler) UpdateUser(c *gin.Context) {
 
 	var req request_model.UpdateUserReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		c.Error(custom_errors.NewInvalidPayloadError(err))
 		return
 	}
 
-	if !config.IsValidEmail(req.Email) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email format"})
-		return
-	}
-
-	updatedUser, err := h.UserService.UpdateUser(&req, id)
+	updatedUser, err := h.UserService.UpdateUser(c.Request.Context(), &req, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		c.Error(err)
 		return
 	}
 
 	profileRes := &response_model.ProfileRes{
-		ID:       updatedUser.ID,
-		FullName: updatedUser.FullName,
-		Email:    updatedUser.Email,
-		Role:     updatedUser.Role,
+		ID:           updatedUser.ID,
+		FullName:     updatedUser.FullName,
+		Email:        updatedUser.Email,
+		Role:         updatedUser.Role,
+		Locale:       updatedUser.Locale,
+		Organization: updatedUser.Organization,
 	}
 
 	c.JSON(http.StatusOK, profileRes)
@@ -96,20 +95,64 @@ func (h *HTTPHandler) UpdateUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 204 {object} string "User deleted successfully"
-// @Failure 400 {object} string "Invalid user ID"
-// @Failure 401 {object} string "Unauthorized"
-// @Failure 404 {object} string "User not found"
-// @Failure 500 {object} string "Server error"
+// @Failure 400 {object} response_model.ProblemRes "Invalid user ID"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "User not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
 // @Security BearerAuth
 // @Router /users [DELETE]
 func (h *HTTPHandler) DeleteUser(c *gin.Context) {
 	id := c.GetInt64("user_id")
 
-	err := h.UserService.DeleteUser(id)
+	err := h.UserService.DeleteUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// SetUserOrganization godoc
+// @Summary Assign a user to an organization
+// @Description Sets the organization whose rate limit quotas the user shares, or removes the user from it when empty. The user's tokens keep the old organization until they expire.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param user_id path int true "User ID"
+// @Param organization body request_model.SetOrganizationReq true "Organization"
+// @Success 200 {object} response_model.ProfileRes "Organization assigned successfully"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 403 {object} response_model.ProblemRes "Forbidden"
+// @Failure 404 {object} response_model.ProblemRes "User not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Router /api/admin/users/{user_id}/organization [PUT]
+func (h *HTTPHandler) SetUserOrganization(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid user ID"))
+		return
+	}
+
+	var req request_model.SetOrganizationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
+		return
+	}
+
+	user, err2 := h.UserService.SetOrganization(c.Request.Context(), int64(id), &req)
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusOK, &response_model.ProfileRes{
+		ID:           user.ID,
+		FullName:     user.FullName,
+		Email:        user.Email,
+		Role:         user.Role,
+		Locale:       user.Locale,
+		Organization: user.Organization,
+	})
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tender-backend/custom_errors"
+	request_model "tender-backend/model/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhook godoc
+// @Summary Register a webhook endpoint
+// @Description Registers an endpoint for the given event types. The returned secret signs every delivery and is shown only once.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param webhook body request_model.CreateWebhookEndpointReq true "Webhook endpoint"
+// @Success 201 {object} response_model.CreateWebhookEndpointRes "Webhook registered successfully"
+// @Failure 400 {object} response_model.ProblemRes "Invalid request payload"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks [POST]
+func (h *HTTPHandler) CreateWebhook(c *gin.Context) {
+	var req request_model.CreateWebhookEndpointReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(custom_errors.NewInvalidPayloadError(err))
+		return
+	}
+
+	endpoint, err := h.WebhookService.CreateEndpoint(c.Request.Context(), c.GetInt64("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// GetWebhooks godoc
+// @Summary Get webhook endpoints
+// @Description Retrieves the webhook endpoints of the authenticated user.
+// @Tags Webhook
+// @Produce json
+// @Success 200 {object} []model.WebhookEndpoint "Webhooks retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks [GET]
+func (h *HTTPHandler) GetWebhooks(c *gin.Context) {
+	endpoints, err := h.WebhookService.GetEndpoints(c.Request.Context(), c.GetInt64("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook endpoint
+// @Description Deletes a webhook endpoint together with its delivery log.
+// @Tags Webhook
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} string "Webhook deleted successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Webhook not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks/{webhook_id} [DELETE]
+func (h *HTTPHandler) DeleteWebhook(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid webhook ID"))
+		return
+	}
+
+	err2 := h.WebhookService.DeleteEndpoint(c.Request.Context(), int64(webhookID), c.GetInt64("user_id"))
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// EnableWebhook godoc
+// @Summary Re-enable a webhook endpoint
+// @Description Re-enables an endpoint that was disabled after repeated delivery failures.
+// @Tags Webhook
+// @Produce json
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} model.WebhookEndpoint "Webhook enabled successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Webhook not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks/{webhook_id}/enable [POST]
+func (h *HTTPHandler) EnableWebhook(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid webhook ID"))
+		return
+	}
+
+	endpoint, err2 := h.WebhookService.EnableEndpoint(c.Request.Context(), int64(webhookID), c.GetInt64("user_id"))
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get the delivery log of a webhook endpoint
+// @Description Retrieves every delivery attempt of an endpoint, newest first.
+// @Tags Webhook
+// @Produce json
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} []model.WebhookDelivery "Deliveries retrieved successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Webhook not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks/{webhook_id}/deliveries [GET]
+func (h *HTTPHandler) GetWebhookDeliveries(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid webhook ID"))
+		return
+	}
+
+	deliveries, err2 := h.WebhookService.GetDeliveries(c.Request.Context(), int64(webhookID), c.GetInt64("user_id"))
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayWebhookDelivery godoc
+// @Summary Replay a webhook delivery
+// @Description Queues the payload of a past delivery again.
+// @Tags Webhook
+// @Produce json
+// @Param webhook_id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDelivery "Delivery queued successfully"
+// @Failure 401 {object} response_model.ProblemRes "Unauthorized"
+// @Failure 404 {object} response_model.ProblemRes "Delivery not found"
+// @Failure 500 {object} response_model.ProblemRes "Server error"
+// @Security BearerAuth
+// @Router /api/webhooks/{webhook_id}/deliveries/{delivery_id}/replay [POST]
+func (h *HTTPHandler) ReplayWebhookDelivery(c *gin.Context) {
+	webhookID, err := strconv.Atoi(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid webhook ID"))
+		return
+	}
+
+	deliveryID, err := strconv.Atoi(c.Param("delivery_id"))
+	if err != nil {
+		c.Error(custom_errors.NewInvalidParameterError("Invalid delivery ID"))
+		return
+	}
+
+	delivery, err2 := h.WebhookService.ReplayDelivery(c.Request.Context(), int64(webhookID), int64(deliveryID), c.GetInt64("user_id"))
+	if err2 != nil {
+		c.Error(err2)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
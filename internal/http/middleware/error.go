@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"tender-backend/custom_errors"
+	"tender-backend/logging"
+	response_model "tender-backend/model/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix makes error codes URIs, as the type of a problem must be.
+const problemTypePrefix = "urn:tender-backend:problem:"
+
+// ErrorMiddleware renders the last error that handlers and the middleware
+// after it added with c.Error as an RFC 7807 problem, unless a response was
+// already written. Handlers add the error and return; middleware also aborts.
+// Errors that are not custom_errors.AppError are answered as internal
+// errors, and the causes of server errors are logged.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := custom_errors.From(c.Errors.Last().Err)
+		if err.StatusCode >= http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), "Request failed", "code", err.Code, "error", err)
+		}
+		writeProblem(c, err)
+	}
+}
+
+// writeProblem writes err as an RFC 7807 problem.
+func writeProblem(c *gin.Context, err *custom_errors.AppError) {
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(err.StatusCode, response_model.ProblemRes{
+		Type:      problemTypePrefix + err.Code,
+		Title:     http.StatusText(err.StatusCode),
+		Status:    err.StatusCode,
+		Detail:    err.Message,
+		Instance:  c.Request.URL.Path,
+		Code:      err.Code,
+		RequestID: logging.RequestID(c.Request.Context()),
+		Errors:    err.Fields,
+	})
+}
+
+// RecoveryMiddleware logs panics of later handlers like gin.Recovery, and
+// leaves answering them with an internal error to ErrorMiddleware.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		c.Error(custom_errors.NewGenericError("Internal server error").Wrap(fmt.Errorf("panic: %v", recovered)))
+		c.Abort()
+	})
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+	"tender-backend/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDMiddleware accepts the X-Request-ID of the caller or generates one,
+// returns it in the response and stores it in the request context, from which
+// logs, services and broker messages pick it up.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+
+		c.Header(logging.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLogMiddleware logs every request once it completed, at error level
+// for server errors.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"duration", time.Since(start),
+			"client_ip", c.ClientIP(),
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		slog.Log(c.Request.Context(), level, "Request", attrs...)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"tender-backend/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware counts requests and records their latency by route
+// pattern. Requests that match no route share one label, so probing random
+// paths cannot create unbounded series.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
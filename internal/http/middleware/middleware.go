@@ -1,18 +1,19 @@
 package middleware
 
 import (
-	"net/http"
+	"crypto/subtle"
+	"tender-backend/custom_errors"
 	"tender-backend/internal/http/token"
 
 	"github.com/gin-gonic/gin"
 )
 
-func JWTMiddleware() gin.HandlerFunc {
+func JWTMiddleware(tokens *token.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenStr := c.Request.Header.Get("Authorization")
 
 		if tokenStr == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "Missing token"})
+			c.Error(custom_errors.NewUnauthorizedError("Missing token"))
 			c.Abort()
 			return
 		}
@@ -20,15 +21,16 @@ func JWTMiddleware() gin.HandlerFunc {
 		// remove Bearer prefix
 		tokenStr = tokenStr[7:]
 
-		claims, err := token.VerifyJWT(tokenStr)
+		claims, err := tokens.VerifyJWT(tokenStr)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Error(custom_errors.NewUnauthorizedError("Invalid or expired token").Wrap(err))
 			c.Abort()
 			return
 		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("organization", claims.Organization)
 		c.Next()
 	}
 }
@@ -37,7 +39,7 @@ func ClientMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role := c.GetString("role")
 		if role != "client" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Only clients can access this resource"})
+			c.Error(custom_errors.NewForbiddenError("Only clients can access this resource"))
 			c.Abort()
 			return
 		}
@@ -49,7 +51,21 @@ func ContractorMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role := c.GetString("role")
 		if role != "contractor" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Only contractors can access this resource"})
+			c.Error(custom_errors.NewForbiddenError("Only contractors can access this resource"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminMiddleware admits requests carrying the configured admin API key in
+// the X-Admin-Key header. Admin routes are disabled while no key is configured.
+func AdminMiddleware(adminAPIKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-Admin-Key")
+		if adminAPIKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(adminAPIKey)) != 1 {
+			c.Error(custom_errors.NewForbiddenError("Only administrators can access this resource"))
 			c.Abort()
 			return
 		}
@@ -1,59 +1,88 @@
 package middleware
 
 import (
-	"net/http"
-	"sync"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"tender-backend/custom_errors"
+	"tender-backend/internal/http/token"
+	"tender-backend/metrics"
+	"tender-backend/rate_limiter"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type clientData struct {
-	timestamps []time.Time
-	mu         sync.Mutex
-}
-
-var (
-	clientRateLimiters = make(map[int64]*clientData)
-	rateLimiterMutex   sync.Mutex
-)
+// APIKeyHeader identifies API clients for policies keyed by api_key.
+const APIKeyHeader = "X-API-Key"
 
-func RateLimitMiddleware(maxRequests int, window time.Duration) gin.HandlerFunc {
+// RateLimitMiddleware applies every policy that matches the request, and only
+// counts the request when all of them allow it. It runs before route
+// authentication, so the token is only parsed here to pick the role and key; a
+// missing or invalid token counts as anonymous. X-API-Key only counts when it
+// is one of apiKeys. The counters live in Redis, so the limits hold across
+// replicas.
+func RateLimitMiddleware(limiter *rate_limiter.Limiter, policies rate_limiter.Policies, tokens *token.Manager, apiKeys []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userId := c.GetInt64("user_id")
-
-		rateLimiterMutex.Lock()
-		clientLimiter, exists := clientRateLimiters[userId]
-		if !exists {
-			clientLimiter = &clientData{timestamps: []time.Time{}}
-			clientRateLimiters[userId] = clientLimiter
+		claims := optionalClaims(c, tokens)
+		role := rate_limiter.RoleAnonymous
+		subject := rate_limiter.Subject{
+			APIKey: rate_limiter.VerifyAPIKey(c.GetHeader(APIKeyHeader), apiKeys),
+			IP:     c.ClientIP(),
+		}
+		if claims != nil {
+			role = claims.Role
+			subject.UserID = claims.UserID
+			subject.Organization = claims.Organization
 		}
-		rateLimiterMutex.Unlock()
-
-		clientLimiter.mu.Lock()
-		defer clientLimiter.mu.Unlock()
 
-		now := time.Now()
-		threshold := now.Add(-window)
-		validTimestamps := []time.Time{}
-		for _, timestamp := range clientLimiter.timestamps {
-			if timestamp.After(threshold) {
-				validTimestamps = append(validTimestamps, timestamp)
+		// The headers describe the policy closest to its limit.
+		result, rejected, err := limiter.Check(c.Request.Context(), policies, c.Request.Method, c.FullPath(), role, subject)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limiter unavailable", "error", err)
+			if limiter.FailOpen {
+				c.Next()
+				return
 			}
+			c.Error(custom_errors.NewServiceUnavailableError("Rate limiter unavailable").Wrap(err))
+			c.Abort()
+			return
 		}
-		clientLimiter.timestamps = validTimestamps
 
-		if len(clientLimiter.timestamps) >= maxRequests {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+		if rejected != nil {
+			metrics.RateLimitRejections.WithLabelValues(rejected.Name).Inc()
+			setRateLimitHeaders(c, result)
+			retryAfter := result.RetryAfter(time.Now())
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.Error(custom_errors.NewTooManyRequestsError("Too many requests, rate limit policy " + rejected.Name + " exceeded"))
+			c.Abort()
 			return
 		}
 
-		clientLimiter.timestamps = append(clientLimiter.timestamps, now)
+		if result != nil {
+			setRateLimitHeaders(c, result)
+		}
+		c.Next()
+	}
+}
 
-		rateLimiterMutex.Lock()
-		clientRateLimiters[userId] = clientLimiter
-		rateLimiterMutex.Unlock()
+func setRateLimitHeaders(c *gin.Context, result *rate_limiter.Result) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(result.Reset.Unix(), 10))
+}
 
-		c.Next()
+// optionalClaims returns the claims of a valid bearer token, or nil.
+func optionalClaims(c *gin.Context, tokens *token.Manager) *token.Claims {
+	tokenStr, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return nil
+	}
+
+	claims, err := tokens.VerifyJWT(tokenStr)
+	if err != nil {
+		return nil
 	}
+	return claims
 }
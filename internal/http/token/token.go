@@ -3,35 +3,51 @@ package token
 import (
 	"fmt"
 	jwt "github.com/golang-jwt/jwt"
-	"tender-backend/config"
 	"time"
 )
 
 type Claims struct {
 	UserID int64  `json:"user_id"`
 	Role   string `json:"role"`
+	// Organization is empty for users that do not belong to one.
+	Organization string `json:"org,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(userID int64, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// Manager issues and verifies access tokens signed with one secret.
+type Manager struct {
+	secret   []byte
+	lifetime time.Duration
+}
+
+// NewManager creates a manager whose tokens are valid for lifetime.
+func NewManager(secret string, lifetime time.Duration) *Manager {
+	return &Manager{
+		secret:   []byte(secret),
+		lifetime: lifetime,
+	}
+}
+
+func (m *Manager) GenerateJWT(userID int64, role, organization string) (string, error) {
+	expirationTime := time.Now().Add(m.lifetime)
 
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:       userID,
+		Role:         role,
+		Organization: organization,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(config.GlobalConfig.SecretKey)
+	return token.SignedString(m.secret)
 }
 
-func VerifyJWT(tokenStr string) (*Claims, error) {
+func (m *Manager) VerifyJWT(tokenStr string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return config.GlobalConfig.SecretKey, nil
+		return m.secret, nil
 	})
 
 	if err != nil {
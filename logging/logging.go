@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestIDHeader is the HTTP header carrying the request ID.
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey names the request ID in log records and broker message headers.
+	RequestIDKey = "request_id"
+)
+
+type requestIDKey struct{}
+
+// Setup makes a logger writing to w the default of both slog and the log
+// package. level is debug, info, warn or error; format is json or text.
+// Records logged with a context carrying a request ID or a trace include them.
+func Setup(w io.Writer, level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q, use json or text", format)
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	// The log package is left with fatal startup errors, logged before exiting.
+	slog.SetLogLoggerLevel(slog.LevelError)
+	return nil
+}
+
+// contextHandler adds the request ID and the trace of the context to every
+// record, so logs can be found from a trace and the other way round.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewRequestID generates a random request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID limits caller supplied request IDs to what is safe to log and
+// forward, such as UUIDs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// ValidRequestID reports whether a request ID supplied by a caller can be
+// used as is.
+func ValidRequestID(id string) bool {
+	return validRequestID.MatchString(id)
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Inject adds the request ID of ctx to the headers of a broker message,
+// allocating them when nil, and returns them.
+func Inject(ctx context.Context, headers map[string]string) map[string]string {
+	id := RequestID(ctx)
+	if id == "" {
+		return headers
+	}
+
+	if headers == nil {
+		headers = make(map[string]string, 1)
+	}
+	headers[RequestIDKey] = id
+	return headers
+}
+
+// Extract returns ctx with the request ID from the headers of a broker
+// message, so consumers log with the ID of the request that caused it.
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	if id := headers[RequestIDKey]; id != "" {
+		return WithRequestID(ctx, id)
+	}
+	return ctx
+}
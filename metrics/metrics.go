@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace prefixes every metric of the service, e.g. tender_http_requests_total.
+const namespace = "tender"
+
+// HTTP requests, labeled by the route pattern rather than the path so IDs do
+// not create a series each.
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// CacheLookups counts cache reads by key family, the store name such as
+// "tender", and result, hit or miss.
+var CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "cache_lookups_total",
+	Help:      "Cache lookups by key family and result.",
+}, []string{"family", "result"})
+
+// RateLimitRejections counts requests answered 429 by the rate limit policy that rejected them.
+var RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limit_rejections_total",
+	Help:      "Requests rejected by rate limit policy.",
+}, []string{"policy"})
+
+// Connections is the number of open notification connections on this
+// instance by transport, websocket, sse or grpc.
+var Connections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "notification_connections",
+	Help:      "Open notification connections by transport.",
+}, []string{"transport"})
+
+// Broker messages. Notification publishes are those to the notifications
+// topics; instance topics are counted together as "notifications.instance".
+var (
+	MessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "broker_messages_published_total",
+		Help:      "Messages published to the broker by topic.",
+	}, []string{"topic"})
+
+	MessagePublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "broker_publish_failures_total",
+		Help:      "Failed broker publishes by topic.",
+	}, []string{"topic"})
+
+	ConsumerLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "broker_consumer_lag_seconds",
+		Help:      "Time from publishing a message to its consumption, by topic.",
+		Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 15, 60, 300},
+	}, []string{"topic"})
+
+	OutboxLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_lag_seconds",
+		Help:      "Age of the oldest outbox event not yet published, 0 when none are pending.",
+	})
+)
+
+// Redis resilience. The breaker state is 0 closed, 1 open and 2 half-open.
+// Cache fallbacks are reads made while Redis was unavailable, and pending
+// invalidations the tags whose invalidation waits for Redis to come back.
+var (
+	CircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "Circuit breaker state by breaker: 0 closed, 1 open, 2 half-open.",
+	}, []string{"breaker"})
+
+	CircuitBreakerOpened = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_opened_total",
+		Help:      "Times a circuit breaker opened, by breaker.",
+	}, []string{"breaker"})
+
+	CircuitBreakerRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_rejections_total",
+		Help:      "Calls rejected while a circuit breaker was open, by breaker.",
+	}, []string{"breaker"})
+
+	CacheFallbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_fallbacks_total",
+		Help:      "Cache reads made while Redis was unavailable.",
+	})
+
+	CachePendingInvalidations = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_pending_invalidations",
+		Help:      "Cache tags whose invalidation waits for Redis to be available.",
+	})
+)
+
+// Notification deliveries by channel: websocket for connected clients, or
+// the offline channels email, sms and webhook.
+var (
+	NotificationsDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_delivered_total",
+		Help:      "Notifications delivered by channel.",
+	}, []string{"channel"})
+
+	NotificationsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_failed_total",
+		Help:      "Failed notification deliveries by channel.",
+	}, []string{"channel"})
+)
+
+// Business gauges, refreshed periodically from the database.
+var (
+	OpenTenders = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "open_tenders",
+		Help:      "Tenders currently open for bids.",
+	})
+
+	BidsLastHour = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bids_submitted_last_hour",
+		Help:      "Bids submitted in the last hour.",
+	})
+)
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS bids;
+DROP TABLE IF EXISTS tenders;
+DROP TABLE IF EXISTS users;
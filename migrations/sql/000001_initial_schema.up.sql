@@ -0,0 +1,52 @@
+-- Baseline schema, exactly as created by AutoMigrate before versioned
+-- migrations. IF NOT EXISTS lets databases created by AutoMigrate adopt it;
+-- the migrations after it add everything introduced since, so they also bring
+-- databases created by later AutoMigrate builds up to date.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial,
+    full_name varchar(255) NOT NULL,
+    password varchar(255) NOT NULL,
+    role varchar(50) NOT NULL,
+    email varchar(255) NOT NULL,
+    username varchar(255) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_users_username UNIQUE (username),
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT chk_users_role CHECK (role IN ('client', 'contractor'))
+);
+
+CREATE TABLE IF NOT EXISTS tenders (
+    id bigserial,
+    client_id bigint NOT NULL,
+    title varchar(255) NOT NULL,
+    description text NOT NULL,
+    deadline timestamptz NOT NULL,
+    budget decimal NOT NULL,
+    status varchar(50) NOT NULL,
+    awarded_contractor_id bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_tenders_status CHECK (status IN ('open', 'closed', 'pending', 'awarded'))
+);
+
+CREATE TABLE IF NOT EXISTS bids (
+    id bigserial,
+    tender_id bigint NOT NULL,
+    contractor_id bigint NOT NULL,
+    price decimal NOT NULL,
+    delivery_time bigint NOT NULL,
+    comments text,
+    status varchar(50) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_bids_status CHECK (status IN ('accepted', 'rejected', 'pending'))
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial,
+    user_id bigint NOT NULL,
+    message text NOT NULL,
+    is_delivered boolean NOT NULL,
+    created_at timestamptz,
+    delivered_at timestamptz,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bids_created_at;
+ALTER TABLE bids DROP COLUMN IF EXISTS created_at;
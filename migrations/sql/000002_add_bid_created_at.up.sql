@@ -0,0 +1,3 @@
+-- Bids existing before this migration are counted as submitted now.
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_bids_created_at ON bids (created_at);
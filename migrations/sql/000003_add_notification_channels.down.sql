@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS notification_preferences;
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_sent_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_type;
@@ -0,0 +1,24 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS event_type varchar(50) NOT NULL DEFAULT '';
+-- Notifications created before offline channels existed count as already
+-- sent, so they are not all emailed at once. The default only fills the rows
+-- present when the column is added.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_sent_at timestamptz DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE notifications ALTER COLUMN fallback_sent_at DROP DEFAULT;
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id bigserial,
+    user_id bigint NOT NULL,
+    event_type varchar(50) NOT NULL,
+    channels varchar(255) NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_event ON notification_preferences (user_id,event_type);
+
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id bigserial,
+    phone varchar(32),
+    webhook_url varchar(2048),
+    quiet_hours_start varchar(5),
+    quiet_hours_end varchar(5),
+    PRIMARY KEY (user_id)
+);
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id bigserial,
+    user_id bigint NOT NULL,
+    url varchar(2048) NOT NULL,
+    secret varchar(128) NOT NULL,
+    event_types varchar(255) NOT NULL,
+    is_active boolean NOT NULL,
+    consecutive_failures bigint NOT NULL DEFAULT 0,
+    disabled_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user_id ON webhook_endpoints (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial,
+    endpoint_id bigint NOT NULL,
+    event_id varchar(64) NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload text NOT NULL,
+    status varchar(50) NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    response_status bigint,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL,
+    delivered_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id ON webhook_deliveries (endpoint_id);
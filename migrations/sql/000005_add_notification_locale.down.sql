@@ -0,0 +1,2 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS payload;
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
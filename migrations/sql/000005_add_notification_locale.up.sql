@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale varchar(8) NOT NULL DEFAULT 'en';
+-- Notifications without a payload keep their stored message in every locale.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS payload text;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
+DROP INDEX IF EXISTS idx_tenders_category;
+ALTER TABLE tenders DROP COLUMN IF EXISTS category;
@@ -0,0 +1,29 @@
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS category varchar(100);
+CREATE INDEX IF NOT EXISTS idx_tenders_category ON tenders (category);
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id bigserial,
+    contractor_id bigint NOT NULL,
+    name varchar(255) NOT NULL,
+    keywords text,
+    categories text,
+    min_budget decimal,
+    max_budget decimal,
+    instant_alerts boolean NOT NULL,
+    digest varchar(50) NOT NULL,
+    last_digest_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT chk_saved_searches_digest CHECK (digest IN ('none', 'daily', 'weekly'))
+);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_contractor_id ON saved_searches (contractor_id);
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    id bigserial,
+    saved_search_id bigint NOT NULL,
+    tender_id bigint NOT NULL,
+    digested_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_tender ON saved_search_matches (saved_search_id,tender_id);
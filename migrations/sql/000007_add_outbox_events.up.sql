@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial,
+    aggregate_type varchar(50) NOT NULL,
+    aggregate_id bigint NOT NULL,
+    topic varchar(255) NOT NULL,
+    payload bytea NOT NULL,
+    headers text,
+    created_at timestamptz,
+    dispatched_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dispatched_at ON outbox_events (dispatched_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox_events (aggregate_type,aggregate_id);
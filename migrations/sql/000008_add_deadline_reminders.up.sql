@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS tender_watches (
+    id bigserial,
+    tender_id bigint NOT NULL,
+    contractor_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watch_tender_contractor ON tender_watches (tender_id,contractor_id);
+CREATE INDEX IF NOT EXISTS idx_tender_watches_contractor_id ON tender_watches (contractor_id);
+
+CREATE TABLE IF NOT EXISTS deadline_reminders (
+    id bigserial,
+    tender_id bigint NOT NULL,
+    offset_minutes bigint NOT NULL,
+    skipped boolean NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminder_tender_offset ON deadline_reminders (tender_id,offset_minutes);
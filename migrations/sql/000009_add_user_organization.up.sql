@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS organization varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE bids DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE bids DROP COLUMN IF EXISTS version;
+ALTER TABLE tenders DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE tenders DROP COLUMN IF EXISTS version;
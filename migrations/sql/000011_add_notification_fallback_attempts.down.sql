@@ -0,0 +1,2 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_next_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_attempts;
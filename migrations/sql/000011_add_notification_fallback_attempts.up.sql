@@ -0,0 +1,2 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_next_at timestamptz;
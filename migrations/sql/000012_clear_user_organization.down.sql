@@ -0,0 +1,2 @@
+-- The cleared organizations cannot be restored.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Organizations were chosen by users at registration and are now assigned by
+-- administrators, so none of the self-declared ones is kept.
+UPDATE users SET organization = '' WHERE organization <> '';
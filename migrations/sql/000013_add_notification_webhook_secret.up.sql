@@ -0,0 +1,7 @@
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS webhook_secret varchar(128);
+
+-- Webhook URLs set before payloads were signed get a secret too; the user
+-- reads it from their notification settings.
+UPDATE notification_settings
+SET webhook_secret = replace(gen_random_uuid()::text || gen_random_uuid()::text, '-', '')
+WHERE webhook_url <> '' AND webhook_secret IS NULL;
@@ -0,0 +1,3 @@
+-- Quiet hours were evaluated in the server time zone, which is UTC in the
+-- deployed containers, so existing settings keep their meaning.
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS time_zone varchar(64) NOT NULL DEFAULT '';
@@ -6,12 +6,14 @@ import (
 
 // User represents the users table.
 type User struct {
-	ID       int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	FullName string `gorm:"size:255;not null" json:"full_name"`
-	Password string `gorm:"size:255;not null" json:"password"`
-	Role     string `gorm:"size:50;not null;check:role IN ('client', 'contractor')" json:"role"` // Restrict role to "client" or "contractor"
-	Email    string `gorm:"size:255;not null;unique" json:"email"`
-	Username string `gorm:"size:255;not null;unique" json:"username"`
+	ID           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	FullName     string `gorm:"size:255;not null" json:"full_name"`
+	Password     string `gorm:"size:255;not null" json:"password"`
+	Role         string `gorm:"size:50;not null;check:role IN ('client', 'contractor')" json:"role"` // Restrict role to "client" or "contractor"
+	Email        string `gorm:"size:255;not null;unique" json:"email"`
+	Username     string `gorm:"size:255;not null;unique" json:"username"`
+	Locale       string `gorm:"size:8;not null;default:'en'" json:"locale"`       // Language notifications are rendered in
+	Organization string `gorm:"size:255;not null;default:''" json:"organization"` // Assigned by administrators, users of one organization share rate limit quotas
 }
 
 // Tender represents the tenders table.
@@ -22,27 +24,195 @@ type Tender struct {
 	Description         string    `gorm:"type:text;not null" json:"description"`
 	Deadline            time.Time `gorm:"not null" json:"deadline"`
 	Budget              float64   `gorm:"not null" json:"budget"`
+	Category            string    `gorm:"size:100;index" json:"category"`
 	Status              string    `gorm:"size:50;not null;check:status IN ('open', 'closed', 'pending', 'awarded')" json:"status"` // Restrict status to predefined values
 	AwardedContractorID int64     `json:"awarded_contractor_id"`
+	Version             int64     `gorm:"not null;default:1" json:"version"` // Incremented on every update, used for ETags and If-Match
+	UpdatedAt           time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 // Bid represents the bids table.
 type Bid struct {
-	ID           int64   `gorm:"primaryKey;autoIncrement" json:"id"`
-	TenderID     int64   `gorm:"not null" json:"tender_id"`
-	ContractorID int64   `gorm:"not null" json:"contractor_id"`
-	Price        float64 `gorm:"not null" json:"price"`
-	DeliveryTime int     `gorm:"not null" json:"delivery_time"`
-	Comments     string  `gorm:"type:text" json:"comments"`
-	Status       string  `gorm:"size:50;not null;check:status IN ('accepted', 'rejected', 'pending')" json:"status"` // Restrict status to predefined values
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenderID     int64     `gorm:"not null" json:"tender_id"`
+	ContractorID int64     `gorm:"not null" json:"contractor_id"`
+	Price        float64   `gorm:"not null" json:"price"`
+	DeliveryTime int       `gorm:"not null" json:"delivery_time"`
+	Comments     string    `gorm:"type:text" json:"comments"`
+	Status       string    `gorm:"size:50;not null;check:status IN ('accepted', 'rejected', 'pending')" json:"status"` // Restrict status to predefined values
+	Version      int64     `gorm:"not null;default:1" json:"version"`                                                  // Incremented on every update, used for ETags and If-Match
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
+// Notification event types.
+const (
+	EventTenderCreated       = "tender_created"
+	EventTenderStatusChanged = "tender_status_changed"
+	EventTenderAwarded       = "tender_awarded"
+	EventBidReceived         = "bid_received"
+	EventTenderMatched       = "tender_matched"
+	EventSavedSearchDigest   = "saved_search_digest"
+	EventTenderDeleted       = "tender_deleted"
+	EventBidDeleted          = "bid_deleted"
+	EventDeadlineReminder    = "deadline_reminder"
+)
+
+// EventTypes lists every event type users can subscribe to.
+var EventTypes = []string{EventTenderCreated, EventTenderStatusChanged, EventTenderAwarded, EventBidReceived}
+
+// NotificationEventTypes lists every event type users are notified of, and so
+// can choose the delivery channels of.
+var NotificationEventTypes = []string{
+	EventTenderStatusChanged, EventTenderAwarded, EventBidReceived,
+	EventTenderMatched, EventSavedSearchDigest, EventDeadlineReminder,
+}
+
+// Saved search digest frequencies.
+const (
+	DigestNone   = "none"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Notification channels a user can choose for offline delivery.
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
 // Notification represents the notifications table.
 type Notification struct {
-	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID      int64      `gorm:"not null" json:"user_id"`
-	Message     string     `gorm:"type:text;not null" json:"message"`
-	IsDelivered bool       `gorm:"not null" json:"is_delivered"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	DeliveredAt *time.Time `json:"delivered_at"`
+	ID             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID         int64      `gorm:"not null" json:"user_id"`
+	EventType      string     `gorm:"size:50;not null;default:''" json:"event_type"`
+	Message        string     `gorm:"type:text;not null" json:"message"`
+	Payload        string     `gorm:"type:text" json:"payload"` // Structured event data as JSON, used to re-render the message
+	IsDelivered    bool       `gorm:"not null" json:"is_delivered"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	FallbackSentAt *time.Time `json:"fallback_sent_at"` // Set once the notification was sent over offline channels
+	// Failed offline sends, and when the next one may start. A worker that
+	// claims the notification moves FallbackNextAt ahead as its lease.
+	FallbackAttempts int        `gorm:"not null;default:0" json:"-"`
+	FallbackNextAt   *time.Time `json:"-"`
+}
+
+// NotificationPreference represents the notification_preferences table.
+// Channels is a comma separated list of offline channels for the event type.
+type NotificationPreference struct {
+	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int64  `gorm:"not null;uniqueIndex:idx_user_event" json:"user_id"`
+	EventType string `gorm:"size:50;not null;uniqueIndex:idx_user_event" json:"event_type"`
+	Channels  string `gorm:"size:255;not null" json:"channels"`
+}
+
+// NotificationSettings represents the notification_settings table.
+// Quiet hours are "HH:MM" strings in TimeZone, an IANA time zone such as
+// "Europe/Berlin", or UTC when it is empty. WebhookSecret signs
+// the notifications sent to WebhookURL; it is generated whenever the URL
+// changes and shown only to the user.
+type NotificationSettings struct {
+	UserID          int64  `gorm:"primaryKey" json:"user_id"`
+	Phone           string `gorm:"size:32" json:"phone"`
+	WebhookURL      string `gorm:"size:2048" json:"webhook_url"`
+	WebhookSecret   string `gorm:"size:128" json:"webhook_secret,omitempty"`
+	QuietHoursStart string `gorm:"size:5" json:"quiet_hours_start"`
+	QuietHoursEnd   string `gorm:"size:5" json:"quiet_hours_end"`
+	TimeZone        string `gorm:"size:64" json:"time_zone"`
+}
+
+// WebhookEndpoint represents the webhook_endpoints table.
+// EventTypes is a comma separated list of subscribed event types.
+type WebhookEndpoint struct {
+	ID                  int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID              int64      `gorm:"not null;index" json:"user_id"`
+	URL                 string     `gorm:"size:2048;not null" json:"url"`
+	Secret              string     `gorm:"size:128;not null" json:"-"`
+	EventTypes          string     `gorm:"size:255;not null" json:"event_types"`
+	IsActive            bool       `gorm:"not null" json:"is_active"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Webhook delivery statuses.
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusSucceeded = "succeeded"
+	WebhookStatusFailed    = "failed"
+)
+
+// WebhookDelivery represents the webhook_deliveries table, the delivery log of an endpoint.
+type WebhookDelivery struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EndpointID     int64      `gorm:"not null;index" json:"endpoint_id"`
+	EventID        string     `gorm:"size:64;not null" json:"event_id"`
+	EventType      string     `gorm:"size:50;not null" json:"event_type"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"size:50;not null;check:status IN ('pending', 'succeeded', 'failed')" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SavedSearch represents the saved_searches table, a contractor's tender search criteria.
+// Keywords and Categories are comma separated lists.
+type SavedSearch struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ContractorID  int64      `gorm:"not null;index" json:"contractor_id"`
+	Name          string     `gorm:"size:255;not null" json:"name"`
+	Keywords      string     `gorm:"type:text" json:"keywords"`
+	Categories    string     `gorm:"type:text" json:"categories"`
+	MinBudget     *float64   `json:"min_budget"`
+	MaxBudget     *float64   `json:"max_budget"`
+	InstantAlerts bool       `gorm:"not null" json:"instant_alerts"`
+	Digest        string     `gorm:"size:50;not null;check:digest IN ('none', 'daily', 'weekly')" json:"digest"`
+	LastDigestAt  *time.Time `json:"last_digest_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// SavedSearchMatch represents the saved_search_matches table, a tender that matched a saved search.
+type SavedSearchMatch struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	SavedSearchID int64      `gorm:"not null;uniqueIndex:idx_search_tender" json:"saved_search_id"`
+	TenderID      int64      `gorm:"not null;uniqueIndex:idx_search_tender" json:"tender_id"`
+	DigestedAt    *time.Time `json:"digested_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OutboxEvent represents the outbox_events table. Events are written in the
+// same transaction as the change they describe and published by the relay.
+type OutboxEvent struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateType string     `gorm:"size:50;not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   int64      `gorm:"not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	Topic         string     `gorm:"size:255;not null" json:"topic"`
+	Payload       []byte     `gorm:"type:bytea;not null" json:"payload"`
+	Headers       string     `gorm:"type:text" json:"headers"` // JSON object of message headers
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	DispatchedAt  *time.Time `gorm:"index" json:"dispatched_at"`
+}
+
+// TenderWatch represents the tender_watches table, a contractor following a
+// tender to get its deadline reminders without bidding.
+type TenderWatch struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenderID     int64     `gorm:"not null;uniqueIndex:idx_watch_tender_contractor" json:"tender_id"`
+	ContractorID int64     `gorm:"not null;uniqueIndex:idx_watch_tender_contractor;index" json:"contractor_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// DeadlineReminder represents the deadline_reminders table. A row is written
+// with the reminder notifications, so each offset fires at most once per tender.
+type DeadlineReminder struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenderID      int64     `gorm:"not null;uniqueIndex:idx_reminder_tender_offset" json:"tender_id"`
+	OffsetMinutes int       `gorm:"not null;uniqueIndex:idx_reminder_tender_offset" json:"offset_minutes"`
+	Skipped       bool      `gorm:"not null" json:"skipped"` // The reminder was superseded by a closer one, e.g. after downtime
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
@@ -1,43 +1,91 @@
 package request_model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Requests are checked with the validate tags by validation.Struct; the
+// custom rules such as role and future are registered there.
 
 type CreateUserReq struct {
-	FullName string `json:"full_name"`
-	Password string `json:"password"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	FullName string `json:"full_name" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,min=5,max=72"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Username string `json:"username" validate:"required,max=255"`
+	Role     string `json:"role" validate:"required,role"`
+	Locale   string `json:"locale" validate:"omitempty,locale"` // Defaults to en
 }
 
 type LoginUserReq struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,max=72"`
 }
 
 type UpdateUserReq struct {
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
+	FullName string `json:"full_name" validate:"required,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Locale   string `json:"locale" validate:"omitempty,locale"`
+}
+
+// SetOrganizationReq assigns a user to an organization, or removes them from
+// it when empty. Only administrators can send it.
+type SetOrganizationReq struct {
+	Organization string `json:"organization" validate:"max=255"`
 }
 
 type CreateBidReq struct {
-	Price        float64 `json:"price"`
-	DeliveryTime int     `json:"delivery_time"`
-	Comments     string  `json:"comments"`
+	Price        float64 `json:"price" validate:"required,money"`
+	DeliveryTime int     `json:"delivery_time" validate:"required,min=1,max=3650"` // In days
+	Comments     string  `json:"comments" validate:"max=2000"`
 }
 
 type CreateTenderReq struct {
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline"`
-	Budget      float64   `json:"budget"`
+	Title       string    `json:"title" validate:"required,max=255"`
+	Description string    `json:"description" validate:"required,max=10000"`
+	Deadline    time.Time `json:"deadline" validate:"required,future"`
+	Budget      float64   `json:"budget" validate:"required,money"`
+	Category    string    `json:"category" validate:"max=100"`
 }
 
 type UpdateTenderReq struct {
-	Status string `json:"status"`
+	Status string `json:"status" validate:"required,tender_status"`
 }
 
 type CreateNotificationReq struct {
-	UserID  int64  `json:"user_id"`
-	Message string `json:"message"`
+	UserID    int64           `json:"user_id" validate:"required"`
+	EventType string          `json:"event_type" validate:"required,max=50"`
+	Message   string          `json:"message"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+type SetNotificationPreferenceReq struct {
+	EventType string   `json:"event_type" validate:"required,notification_event"`
+	Channels  []string `json:"channels" validate:"max=3,dive,channel"`
+}
+
+// UpdateNotificationSettingsReq sets either both quiet hours or neither.
+type UpdateNotificationSettingsReq struct {
+	Phone           string `json:"phone" validate:"omitempty,e164"`
+	WebhookURL      string `json:"webhook_url" validate:"omitempty,http_url,public_url,max=2048"`
+	QuietHoursStart string `json:"quiet_hours_start" validate:"omitempty,clock"`
+	QuietHoursEnd   string `json:"quiet_hours_end" validate:"omitempty,clock"`
+	TimeZone        string `json:"time_zone" validate:"omitempty,timezone,max=64"` // Of the quiet hours, defaults to UTC
+}
+
+type CreateWebhookEndpointReq struct {
+	URL        string   `json:"url" validate:"required,http_url,public_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,max=10,dive,event_type"`
+}
+
+// CreateSavedSearchReq needs at least one criterion, and MinBudget cannot
+// exceed MaxBudget.
+type CreateSavedSearchReq struct {
+	Name          string   `json:"name" validate:"required,max=255"`
+	Keywords      []string `json:"keywords" validate:"max=20,dive,required,max=100"`
+	Categories    []string `json:"categories" validate:"max=20,dive,required,max=100"`
+	MinBudget     *float64 `json:"min_budget" validate:"omitnil,gte=0"`
+	MaxBudget     *float64 `json:"max_budget" validate:"omitnil,money"`
+	InstantAlerts *bool    `json:"instant_alerts"`                     // Defaults to true
+	Digest        string   `json:"digest" validate:"omitempty,digest"` // none, daily or weekly
 }
@@ -1,13 +1,79 @@
 package response_model
 
+import (
+	"tender-backend/cache"
+	"tender-backend/circuit_breaker"
+	"tender-backend/custom_errors"
+	"time"
+)
+
 type ProfileRes struct {
-	ID       int64  `json:"id"`
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	ID           int64  `json:"id"`
+	FullName     string `json:"full_name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Locale       string `json:"locale"`
+	Organization string `json:"organization"`
 }
 
 type LoginRes struct {
 	Token string `json:"token"`
 	Role  string `json:"role"`
 }
+
+// CreateWebhookEndpointRes carries the signing secret, which is only returned once.
+type CreateWebhookEndpointRes struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+// HealthRes reports the dependencies the service can run without. Status is
+// "degraded" while Redis is unavailable and requests are served from PostgreSQL.
+type HealthRes struct {
+	Status string                `json:"status"`
+	Redis  circuit_breaker.Stats `json:"redis"`
+	Cache  cache.Stats           `json:"cache"`
+}
+
+// ReadinessRes reports every dependency checked by the readiness probe.
+// Status is "ok", "degraded" while only Redis is unavailable, "unavailable"
+// while PostgreSQL or the broker is, and "draining" during shutdown.
+type ReadinessRes struct {
+	Status string                    `json:"status"`
+	Checks map[string]DependencyRes `json:"checks"`
+}
+
+// DependencyRes is the result of checking one dependency.
+type DependencyRes struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// RateLimitUsageRes is the current window of one rate limit key.
+type RateLimitUsageRes struct {
+	Key       string    `json:"key"`
+	Policy    string    `json:"policy"`
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// ProblemRes is the RFC 7807 body of every error response, served as
+// application/problem+json.
+type ProblemRes struct {
+	// Type is a URI identifying the code.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request path.
+	Instance string `json:"instance,omitempty"`
+	// Code is the stable machine-readable error code, e.g. tender_not_found.
+	Code      string                     `json:"code"`
+	RequestID string                     `json:"request_id,omitempty"`
+	Errors    []custom_errors.FieldError `json:"errors,omitempty"`
+}
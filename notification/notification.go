@@ -1,16 +1,33 @@
 package notification
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
-	"tender-backend/db"
+	"tender-backend/custom_errors"
+	"tender-backend/metrics"
 	"tender-backend/server"
+	"tender-backend/web_socket"
+	"time"
 )
 
+// ErrShuttingDown is returned by Stream once the server is shutting down.
+var ErrShuttingDown = errors.New("notification server is shutting down")
+
+// sseHeartbeatInterval keeps idle event streams open through proxies.
+const sseHeartbeatInterval = 25 * time.Second
+
+// closeTimeout is how long a WebSocket client has to answer the close frame
+// sent on shutdown before the connection is dropped.
+const closeTimeout = 5 * time.Second
+
 var upgrader = websocket.Upgrader{
+	Subprotocols: web_socket.Subprotocols,
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Adjust this for security
 	},
@@ -18,56 +35,292 @@ var upgrader = websocket.Upgrader{
 
 type Client struct {
 	UserID int64
-	Conn   *websocket.Conn
-	Send   chan []byte
+	Conn   web_socket.Connection
+	// ctx carries the request ID of the connection request, and outlives it.
+	ctx context.Context
 }
 
 type Server struct {
-	Clients   map[*Client]bool
-	Register  chan *Client
-	Broadcast chan []byte
-	mu        sync.Mutex
-	ns        *server.NotificationService
+	Clients  map[*Client]bool
+	Register chan *Client
+	mu       sync.Mutex
+	ns       *server.NotificationService
+
+	// closing is closed on shutdown; handlers tracks the open connections,
+	// which http.Server.Shutdown does not wait for once hijacked.
+	closing  chan struct{}
+	closed   bool
+	handlers sync.WaitGroup
 }
 
-func NewNotificationServer() *Server {
+func NewNotificationServer(ns *server.NotificationService) *Server {
 	return &Server{
 		Clients:  make(map[*Client]bool),
 		Register: make(chan *Client),
-		ns:       server.NewNotificationService(db.DB),
+		ns:       ns,
+		closing:  make(chan struct{}),
+	}
+}
+
+// Shutdown refuses new connections, closes every WebSocket with a close frame
+// and ends every event stream, then waits until their handlers returned or
+// ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.closing)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
+// track counts a connection handler, unless the server is shutting down.
+func (s *Server) track(c *gin.Context) bool {
+	if !s.add() {
+		c.Error(custom_errors.NewServiceUnavailableError("Server is shutting down"))
+		return false
+	}
+	return true
+}
+
+// add counts a connection handler and reports false once the server is
+// shutting down.
+func (s *Server) add() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.handlers.Add(1)
+	return true
+}
+
 func (s *Server) Run() {
 	for client := range s.Register {
 		s.mu.Lock()
 		s.Clients[client] = true
 		s.mu.Unlock()
-		log.Println("Client registered")
+		slog.Debug("Client registered", "user_id", client.UserID)
 		go func() {
-			err := s.ns.PublishNotDeliveredNotificationsForUser(client.UserID)
+			err := s.ns.PublishNotDeliveredNotificationsForUser(client.ctx, client.UserID)
 
 			if err != nil {
-				log.Printf("Failed to publish notifications for user: %v", err)
+				slog.ErrorContext(client.ctx, "Failed to publish undelivered notifications", "user_id", client.UserID, "error", err)
 			}
 		}()
 	}
 }
 
+// register adds the client to the shared connection registry and announces
+// its user's presence on this instance.
+func (s *Server) register(client *Client) {
+	if web_socket.RegisterClient(client.UserID, client.Conn) {
+		if err := s.ns.UserConnected(client.UserID); err != nil {
+			slog.ErrorContext(client.ctx, "Failed to record presence", "user_id", client.UserID, "error", err)
+		}
+	}
+}
+
+func (s *Server) unregister(client *Client) {
+	if web_socket.UnregisterClient(client.UserID, client.Conn) {
+		if err := s.ns.UserDisconnected(client.UserID); err != nil {
+			slog.ErrorContext(client.ctx, "Failed to clear presence", "user_id", client.UserID, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.Clients, client)
+	s.mu.Unlock()
+}
+
 func (s *Server) HandleConnection(c *gin.Context) {
+	if !s.track(c) {
+		return
+	}
+	defer s.handlers.Done()
+
 	// Upgrade the HTTP connection to a WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		log.Printf("Failed to upgrade connection: %v", err)
+		slog.WarnContext(c.Request.Context(), "Failed to upgrade connection", "error", err)
 		return
 	}
+	defer conn.Close()
 
 	userId := c.GetInt64("user_id")
 
+	wsConn := web_socket.NewWebSocketConnection(conn)
+	client := &Client{
+		Conn:   wsConn,
+		UserID: userId,
+		ctx:    context.WithoutCancel(c.Request.Context()),
+	}
+	s.register(client)
+	defer s.unregister(client)
+
+	connections := metrics.Connections.WithLabelValues("websocket")
+	connections.Inc()
+	defer connections.Dec()
+
+	s.Register <- client
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.closing:
+			// The answer of the client ends the read loop below, or the deadline does.
+			if err := wsConn.CloseGoingAway(closeTimeout); err != nil {
+				conn.Close()
+				return
+			}
+			_ = conn.SetReadDeadline(time.Now().Add(closeTimeout))
+		case <-done:
+		}
+	}()
+
+	// Read until the client goes away so the connection gets unregistered.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// Stream pushes the notifications of the user to conn, starting with the
+// undelivered ones, until ctx is done or the server shuts down, when it
+// returns ErrShuttingDown. It serves connections that are not upgraded from
+// an HTTP request, such as gRPC streams, labelled transport in metrics.
+func (s *Server) Stream(ctx context.Context, transport string, userID int64, conn web_socket.Connection) error {
+	if !s.add() {
+		return ErrShuttingDown
+	}
+	defer s.handlers.Done()
+
 	client := &Client{
 		Conn:   conn,
-		Send:   make(chan []byte),
+		UserID: userID,
+		ctx:    context.WithoutCancel(ctx),
+	}
+	s.register(client)
+	defer s.unregister(client)
+
+	connections := metrics.Connections.WithLabelValues(transport)
+	connections.Inc()
+	defer connections.Dec()
+
+	s.Register <- client
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-s.closing:
+		return ErrShuttingDown
+	}
+}
+
+// HandleSSE streams notifications as Server-Sent Events for clients that
+// cannot open a WebSocket. When the client sends Last-Event-ID, every
+// notification after that ID is replayed before live events. The client is
+// registered before the replay so no live event is missed, and the live
+// events arriving meanwhile are held back until it ends. Either way, the
+// undelivered notifications are then published again, like for WebSockets.
+func (s *Server) HandleSSE(c *gin.Context) {
+	if !s.track(c) {
+		return
+	}
+	defer s.handlers.Done()
+
+	conn, err := web_socket.NewSSEConnection(c.Writer)
+	if err != nil {
+		c.Error(custom_errors.NewAppError(err))
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource cannot set headers on the first request.
+		lastEventID = c.Query("last_event_id")
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		lastID, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			c.Error(custom_errors.NewInvalidParameterError("Invalid Last-Event-ID"))
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if err := conn.Ping(); err != nil {
+		return
+	}
+
+	userId := c.GetInt64("user_id")
+
+	var live web_socket.Connection = conn
+	var resuming *resumingConnection
+	if lastEventID != "" {
+		resuming = newResumingConnection(conn, lastID)
+		live = resuming
+	}
+
+	client := &Client{
+		Conn:   live,
 		UserID: userId,
+		ctx:    context.WithoutCancel(c.Request.Context()),
+	}
+	s.register(client)
+	defer s.unregister(client)
+
+	connections := metrics.Connections.WithLabelValues("sse")
+	connections.Inc()
+	defer connections.Dec()
+
+	if resuming != nil {
+		if err := s.ns.ReplayNotificationsAfter(c.Request.Context(), userId, lastID, replayConnection{resuming}); err != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to replay notifications", "user_id", userId, "error", err)
+			return
+		}
+		if err := resuming.resume(); err != nil {
+			return
+		}
 	}
 	s.Register <- client
+
+	ticker := time.NewTicker(sseHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-s.closing:
+			// EventSource reconnects on its own, with Last-Event-ID.
+			return
+		case <-ticker.C:
+			if err := conn.Ping(); err != nil {
+				return
+			}
+		}
+	}
 }
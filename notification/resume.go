@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"sort"
+	"sync"
+	"tender-backend/gen_proto"
+	"tender-backend/web_socket"
+)
+
+// resumingConnection holds back the live notifications of a reconnecting
+// client while the ones it missed are replayed, so event IDs stay in order.
+// Afterwards it drops live notifications the client already got, either
+// before it reconnected or in the replay.
+type resumingConnection struct {
+	conn web_socket.Connection
+
+	mu        sync.Mutex
+	replaying bool
+	held      []*gen_proto.Notification
+	// replayedID is the highest ID the client got.
+	replayedID int64
+}
+
+func newResumingConnection(conn web_socket.Connection, lastID int64) *resumingConnection {
+	return &resumingConnection{conn: conn, replaying: true, replayedID: lastID}
+}
+
+func (c *resumingConnection) Send(notification *gen_proto.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replaying {
+		c.held = append(c.held, notification)
+		return nil
+	}
+	if notification.Id <= c.replayedID {
+		return nil
+	}
+	return c.conn.Send(notification)
+}
+
+// resume sends the held notifications that were not replayed, in ID order,
+// and lets live notifications through from then on.
+func (c *resumingConnection) resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.replaying = false
+	held := c.held
+	c.held = nil
+
+	sort.Slice(held, func(i, j int) bool { return held[i].Id < held[j].Id })
+	for _, notification := range held {
+		if notification.Id <= c.replayedID {
+			continue
+		}
+		if err := c.conn.Send(notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayConnection writes the replayed notifications straight to the client.
+type replayConnection struct {
+	c *resumingConnection
+}
+
+func (r replayConnection) Send(notification *gen_proto.Notification) error {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	if err := r.c.conn.Send(notification); err != nil {
+		return err
+	}
+	r.c.replayedID = max(r.c.replayedID, notification.Id)
+	return nil
+}
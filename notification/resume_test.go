@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"slices"
+	"tender-backend/gen_proto"
+	"testing"
+)
+
+type recordingConnection struct {
+	ids []int64
+}
+
+func (c *recordingConnection) Send(notification *gen_proto.Notification) error {
+	c.ids = append(c.ids, notification.Id)
+	return nil
+}
+
+func TestResumingConnection(t *testing.T) {
+	conn := &recordingConnection{}
+	resuming := newResumingConnection(conn, 10)
+
+	// Live notifications arrive while 11 and 12 are replayed
+	_ = resuming.Send(&gen_proto.Notification{Id: 13})
+	_ = replayConnection{resuming}.Send(&gen_proto.Notification{Id: 11})
+	_ = resuming.Send(&gen_proto.Notification{Id: 12})
+	_ = replayConnection{resuming}.Send(&gen_proto.Notification{Id: 12})
+	if err := resuming.resume(); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+
+	// Republished notifications the client already got are dropped
+	_ = resuming.Send(&gen_proto.Notification{Id: 9})
+	_ = resuming.Send(&gen_proto.Notification{Id: 14})
+
+	if want := []int64{11, 12, 13, 14}; !slices.Equal(conn.ids, want) {
+		t.Errorf("sent %v, want %v", conn.ids, want)
+	}
+}
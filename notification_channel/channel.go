@@ -0,0 +1,30 @@
+package notification_channel
+
+import (
+	"errors"
+	"tender-backend/config"
+	"tender-backend/model"
+)
+
+var ErrNoAddress = errors.New("recipient has no address for this channel")
+
+// Recipient holds everything a channel may need to reach a user.
+type Recipient struct {
+	User     *model.User
+	Settings *model.NotificationSettings
+}
+
+// Channel delivers a notification to a user outside the WebSocket connection.
+type Channel interface {
+	Name() string
+	Send(recipient *Recipient, notification *model.Notification) error
+}
+
+// DefaultChannels builds the email, SMS and webhook offline channels.
+func DefaultChannels(smtp config.SMTPConfig) []Channel {
+	return []Channel{
+		NewEmailChannel(smtp),
+		NewSMSChannel(NewLogSMSProvider()),
+		NewWebhookChannel(),
+	}
+}
@@ -0,0 +1,41 @@
+package notification_channel
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"tender-backend/config"
+	"tender-backend/model"
+)
+
+type EmailChannel struct {
+	cfg config.SMTPConfig
+}
+
+func NewEmailChannel(cfg config.SMTPConfig) *EmailChannel {
+	return &EmailChannel{cfg: cfg}
+}
+
+func (c *EmailChannel) Name() string {
+	return model.ChannelEmail
+}
+
+func (c *EmailChannel) Send(recipient *Recipient, notification *model.Notification) error {
+	if recipient.User == nil || recipient.User.Email == "" {
+		return ErrNoAddress
+	}
+
+	if c.cfg.Host == "" {
+		return errors.New("smtp is not configured")
+	}
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Tender notification\r\n\r\n%s\r\n",
+		c.cfg.From, recipient.User.Email, notification.Message)
+
+	return smtp.SendMail(c.cfg.Host+":"+c.cfg.Port, auth, c.cfg.From, []string{recipient.User.Email}, []byte(body))
+}
@@ -0,0 +1,43 @@
+package notification_channel
+
+import (
+	"log/slog"
+	"tender-backend/model"
+)
+
+// SMSProvider sends a text message to a phone number.
+type SMSProvider interface {
+	SendSMS(phone, text string) error
+}
+
+// LogSMSProvider is a local stub that only logs outgoing messages.
+type LogSMSProvider struct{}
+
+func NewLogSMSProvider() *LogSMSProvider {
+	return &LogSMSProvider{}
+}
+
+func (p *LogSMSProvider) SendSMS(phone, text string) error {
+	slog.Info("SMS", "phone", phone, "text", text)
+	return nil
+}
+
+type SMSChannel struct {
+	provider SMSProvider
+}
+
+func NewSMSChannel(provider SMSProvider) *SMSChannel {
+	return &SMSChannel{provider: provider}
+}
+
+func (c *SMSChannel) Name() string {
+	return model.ChannelSMS
+}
+
+func (c *SMSChannel) Send(recipient *Recipient, notification *model.Notification) error {
+	if recipient.Settings == nil || recipient.Settings.Phone == "" {
+		return ErrNoAddress
+	}
+
+	return c.provider.SendSMS(recipient.Settings.Phone, notification.Message)
+}
@@ -0,0 +1,65 @@
+package notification_channel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"tender-backend/model"
+	"tender-backend/safe_http"
+	"time"
+)
+
+// WebhookEventHeader names the event type of a notification webhook.
+const WebhookEventHeader = "X-Tender-Event"
+
+// WebhookChannel posts notifications to the user's webhook URL, signed like
+// the webhooks of registered endpoints with the secret of the user's
+// notification settings.
+type WebhookChannel struct {
+	client *http.Client
+}
+
+func NewWebhookChannel() *WebhookChannel {
+	return &WebhookChannel{
+		// Webhook URLs are user-supplied, so they must not reach internal addresses.
+		client: safe_http.NewClient(10 * time.Second),
+	}
+}
+
+func (c *WebhookChannel) Name() string {
+	return model.ChannelWebhook
+}
+
+func (c *WebhookChannel) Send(recipient *Recipient, notification *model.Notification) error {
+	if recipient.Settings == nil || recipient.Settings.WebhookURL == "" {
+		return ErrNoAddress
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, recipient.Settings.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, notification.EventType)
+	safe_http.SetSignature(req, recipient.Settings.WebhookSecret, payload, time.Now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package notification_channel
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"tender-backend/model"
+	"tender-backend/safe_http"
+	"testing"
+)
+
+func TestWebhookChannelSignsPayload(t *testing.T) {
+	var signature, timestamp string
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(safe_http.SignatureHeader)
+		timestamp = r.Header.Get(safe_http.TimestampHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	channel := NewWebhookChannel()
+	// The receiver listens on loopback, which the production client refuses
+	channel.client = receiver.Client()
+
+	recipient := &Recipient{Settings: &model.NotificationSettings{WebhookURL: receiver.URL, WebhookSecret: "secret"}}
+	if err := channel.Send(recipient, &model.Notification{EventType: model.EventTenderAwarded, Message: "Awarded"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	want := "sha256=" + safe_http.Sign("secret", timestamp, body)
+	if signature != want || !strings.Contains(string(body), "Awarded") {
+		t.Errorf("signature %q for body %s, want %q", signature, body, want)
+	}
+}
+
+func TestWebhookChannelRefusesInternalURL(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("internal URL was called")
+	}))
+	defer receiver.Close()
+
+	recipient := &Recipient{Settings: &model.NotificationSettings{WebhookURL: receiver.URL, WebhookSecret: "secret"}}
+	if err := NewWebhookChannel().Send(recipient, &model.Notification{EventType: model.EventTenderAwarded}); err == nil {
+		t.Fatal("send to a loopback URL succeeded")
+	}
+}
@@ -0,0 +1,131 @@
+package notification_template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const DefaultLocale = "en"
+
+// SupportedLocales lists the locales every default template is available in.
+var SupportedLocales = []string{"en", "ru", "uz"}
+
+var funcs = template.FuncMap{
+	"money": func(v interface{}) string {
+		if f, ok := v.(float64); ok {
+			return fmt.Sprintf("%.2f", f)
+		}
+		return fmt.Sprint(v)
+	},
+	"date": func(v interface{}) string {
+		if s, ok := v.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t.Format("2006-01-02 15:04")
+			}
+		}
+		return fmt.Sprint(v)
+	},
+}
+
+// Registry holds notification templates keyed by event type and locale.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]map[string]*template.Template // map[event_type]map[locale]template
+}
+
+// NewRegistry returns a registry preloaded with the default templates.
+func NewRegistry() *Registry {
+	r := &Registry{templates: make(map[string]map[string]*template.Template)}
+	for eventType, byLocale := range defaultTemplates {
+		for locale, text := range byLocale {
+			if err := r.Register(eventType, locale, text); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return r
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the shared registry with the built-in templates.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds or replaces the template for an event type in one locale.
+func (r *Registry) Register(eventType, locale, text string) error {
+	tmpl, err := template.New(eventType + "." + locale).Funcs(funcs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template %s.%s: %w", eventType, locale, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.templates[eventType] == nil {
+		r.templates[eventType] = make(map[string]*template.Template)
+	}
+	r.templates[eventType][locale] = tmpl
+	return nil
+}
+
+// Has reports whether any template exists for the event type.
+func (r *Registry) Has(eventType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.templates[eventType]) > 0
+}
+
+// Render renders the event payload, a JSON object, in the given locale.
+// Unknown locales fall back to English.
+func (r *Registry) Render(eventType, locale string, payload []byte) (string, error) {
+	r.mu.RLock()
+	byLocale := r.templates[eventType]
+	tmpl, ok := byLocale[NormalizeLocale(locale)]
+	if !ok {
+		tmpl, ok = byLocale[DefaultLocale]
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("no template for event type %q", eventType)
+	}
+
+	data := map[string]interface{}{}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// NormalizeLocale reduces tags such as "ru-RU" to their language, "ru".
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// IsSupportedLocale reports whether templates are provided for the locale.
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
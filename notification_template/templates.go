@@ -0,0 +1,45 @@
+package notification_template
+
+import "tender-backend/model"
+
+// defaultTemplates holds the built-in templates, map[event_type]map[locale]text.
+var defaultTemplates = map[string]map[string]string{
+	model.EventTenderCreated: {
+		"en": `New tender "{{.title}}" with a budget of {{money .budget}} is open until {{date .deadline}}.`,
+		"ru": `Новый тендер «{{.title}}» с бюджетом {{money .budget}} открыт до {{date .deadline}}.`,
+		"uz": `Yangi «{{.title}}» tenderi {{money .budget}} byudjet bilan {{date .deadline}} gacha ochiq.`,
+	},
+	model.EventTenderStatusChanged: {
+		"en": `Tender "{{.title}}" is now {{.status}}.`,
+		"ru": `Статус тендера «{{.title}}» изменён на {{.status}}.`,
+		"uz": `«{{.title}}» tenderining holati {{.status}} ga o'zgardi.`,
+	},
+	model.EventTenderAwarded: {
+		"en": `Tender "{{.title}}" has been awarded.`,
+		"ru": `Тендер «{{.title}}» присуждён.`,
+		"uz": `«{{.title}}» tenderi g'olibi aniqlandi.`,
+	},
+	model.EventBidReceived: {
+		"en": `New bid of {{money .price}} with delivery in {{.delivery_time}} days for tender "{{.title}}".`,
+		"ru": `Новое предложение на {{money .price}} со сроком поставки {{.delivery_time}} дн. по тендеру «{{.title}}».`,
+		"uz": `«{{.title}}» tenderiga {{money .price}} miqdorida, {{.delivery_time}} kunda yetkazib berish bilan yangi taklif.`,
+	},
+	model.EventTenderMatched: {
+		"en": `Tender "{{.title}}" with a budget of {{money .budget}} matches your saved search "{{.search_name}}".`,
+		"ru": `Тендер «{{.title}}» с бюджетом {{money .budget}} соответствует вашему поиску «{{.search_name}}».`,
+		"uz": `{{money .budget}} byudjetli «{{.title}}» tenderi saqlangan «{{.search_name}}» qidiruvingizga mos keladi.`,
+	},
+	model.EventSavedSearchDigest: {
+		"en": `{{len .tenders}} new tenders match your saved search "{{.search_name}}":{{range .tenders}}
+- "{{.title}}", budget {{money .budget}}, deadline {{date .deadline}}{{end}}`,
+		"ru": `Новых тендеров по вашему поиску «{{.search_name}}»: {{len .tenders}}{{range .tenders}}
+- «{{.title}}», бюджет {{money .budget}}, срок {{date .deadline}}{{end}}`,
+		"uz": `Saqlangan «{{.search_name}}» qidiruvingizga {{len .tenders}} ta yangi tender mos keladi:{{range .tenders}}
+- «{{.title}}», byudjet {{money .budget}}, muddat {{date .deadline}}{{end}}`,
+	},
+	model.EventDeadlineReminder: {
+		"en": `Tender "{{.title}}" closes in {{.hours_left}} h, on {{date .deadline}}.`,
+		"ru": `Приём предложений по тендеру «{{.title}}» закрывается через {{.hours_left}} ч., {{date .deadline}}.`,
+		"uz": `«{{.title}}» tenderi {{.hours_left}} soatdan keyin, {{date .deadline}} da yopiladi.`,
+	},
+}
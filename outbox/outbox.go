@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"tender-backend/broker"
+	"tender-backend/metrics"
+	"tender-backend/model"
+	"tender-backend/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	batchSize = 100
+	retention = 7 * 24 * time.Hour
+
+	// relayLockKey is the advisory lock that lets only one relay publish at a
+	// time, which keeps events of an aggregate in order across replicas.
+	relayLockKey = 7_220_330
+)
+
+// Write stores a message in the outbox as part of tx. It is published to the
+// broker only if tx commits, in the trace of the context of tx.
+func Write(tx *gorm.DB, aggregateType string, aggregateID int64, topic string, msg broker.Message) error {
+	msg.Headers = tracing.Inject(tx.Statement.Context, msg.Headers)
+
+	event := model.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Topic:         topic,
+		Payload:       msg.Body,
+	}
+
+	if len(msg.Headers) > 0 {
+		headers, err := json.Marshal(msg.Headers)
+		if err != nil {
+			return err
+		}
+		event.Headers = string(headers)
+	}
+
+	return tx.Create(&event).Error
+}
+
+// Relay publishes outbox events to the broker and marks them dispatched.
+type Relay struct {
+	db     *gorm.DB
+	broker broker.Broker
+}
+
+func NewRelay(db *gorm.DB, b broker.Broker) *Relay {
+	return &Relay{
+		db:     db,
+		broker: b,
+	}
+}
+
+// Run polls the outbox until ctx is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := r.dispatch(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to dispatch outbox events", "error", err)
+			}
+			r.recordLag(ctx, now)
+
+			if now.Sub(lastCleanup) >= time.Hour {
+				r.cleanup(now)
+				lastCleanup = now
+			}
+		}
+	}
+}
+
+func (r *Relay) dispatch(ctx context.Context) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+
+		// Another replica is relaying.
+		if !locked {
+			return nil
+		}
+
+		var events []model.OutboxEvent
+		if err := tx.Where("dispatched_at IS NULL").Order("id").Limit(batchSize).Find(&events).Error; err != nil {
+			return err
+		}
+
+		// Once an event of an aggregate fails, its later events wait for the
+		// next round so they are never published out of order.
+		blocked := make(map[string]bool)
+		for i := range events {
+			aggregate := fmt.Sprintf("%s:%d", events[i].AggregateType, events[i].AggregateID)
+			if blocked[aggregate] {
+				continue
+			}
+
+			if err := r.publish(ctx, &events[i]); err != nil {
+				slog.ErrorContext(ctx, "Failed to publish outbox event", "event_id", events[i].ID, "topic", events[i].Topic, "error", err)
+				blocked[aggregate] = true
+				continue
+			}
+
+			if err := tx.Model(&events[i]).Update("dispatched_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *Relay) publish(ctx context.Context, event *model.OutboxEvent) error {
+	msg := broker.Message{Body: event.Payload}
+	if event.Headers != "" {
+		if err := json.Unmarshal([]byte(event.Headers), &msg.Headers); err != nil {
+			return err
+		}
+	}
+
+	return r.broker.Publish(ctx, event.Topic, msg)
+}
+
+// recordLag sets the outbox lag metric to the age of the oldest pending event.
+func (r *Relay) recordLag(ctx context.Context, now time.Time) {
+	var oldest *time.Time
+	if err := r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("dispatched_at IS NULL").
+		Select("MIN(created_at)").Scan(&oldest).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to measure outbox lag", "error", err)
+		return
+	}
+
+	if oldest == nil {
+		metrics.OutboxLag.Set(0)
+		return
+	}
+	metrics.OutboxLag.Set(now.Sub(*oldest).Seconds())
+}
+
+func (r *Relay) cleanup(now time.Time) {
+	if err := r.db.Where("dispatched_at < ?", now.Add(-retention)).Delete(&model.OutboxEvent{}).Error; err != nil {
+		slog.Error("Failed to clean up outbox events", "error", err)
+	}
+}
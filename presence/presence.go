@@ -0,0 +1,92 @@
+package presence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// TTL is how long a presence entry lives without a heartbeat, so users
+	// connected to a crashed instance stop being routed there.
+	TTL               = 90 * time.Second
+	HeartbeatInterval = 30 * time.Second
+)
+
+// Tracker records which API instances hold a live connection for each user.
+// Every user has a sorted set of instance IDs scored by their expiry time.
+type Tracker struct {
+	redis      *redis.Client
+	instanceID string
+}
+
+func NewTracker(redisClient *redis.Client, instanceID string) *Tracker {
+	return &Tracker{
+		redis:      redisClient,
+		instanceID: instanceID,
+	}
+}
+
+func (t *Tracker) InstanceID() string {
+	return t.instanceID
+}
+
+func presenceKey(userID int64) string {
+	return fmt.Sprintf("presence:user:%d", userID)
+}
+
+// Join marks the user as connected to this instance.
+func (t *Tracker) Join(ctx context.Context, userID int64) error {
+	return t.touch(ctx, userID, time.Now())
+}
+
+// Leave removes this instance from the user's presence.
+func (t *Tracker) Leave(ctx context.Context, userID int64) error {
+	return t.redis.ZRem(ctx, presenceKey(userID), t.instanceID).Err()
+}
+
+// Instances returns the instances the user is currently connected to.
+func (t *Tracker) Instances(ctx context.Context, userID int64) ([]string, error) {
+	key := presenceKey(userID)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	if err := t.redis.ZRemRangeByScore(ctx, key, "-inf", "("+now).Err(); err != nil {
+		return nil, err
+	}
+
+	return t.redis.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+}
+
+// Run refreshes the presence of every locally connected user until ctx is done.
+func (t *Tracker) Run(ctx context.Context, onlineUsers func() []int64) {
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, userID := range onlineUsers() {
+				if err := t.touch(ctx, userID, now); err != nil {
+					slog.ErrorContext(ctx, "Failed to refresh presence", "user_id", userID, "error", err)
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracker) touch(ctx context.Context, userID int64, now time.Time) error {
+	key := presenceKey(userID)
+
+	_, err := t.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Add(TTL).Unix()), Member: t.instanceID})
+		pipe.Expire(ctx, key, TTL)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,94 @@
+package rate_limiter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Subject is what a request can be counted by. Fields are zero when unknown,
+// e.g. UserID for anonymous requests.
+type Subject struct {
+	UserID       int64
+	Organization string
+	// APIKey is a key returned by VerifyAPIKey, never one a client only claims.
+	APIKey string
+	IP     string
+}
+
+// Key returns what a policy with the given key counts the subject by, e.g.
+// "user:42". Subjects that lack the policy key fall back to the user and
+// then the IP.
+func (s Subject) Key(policyKey string) string {
+	switch policyKey {
+	case KeyAPIKey:
+		if s.APIKey != "" {
+			// Only a hash is stored, so Redis never holds the key itself.
+			sum := sha256.Sum256([]byte(s.APIKey))
+			return "api_key:" + hex.EncodeToString(sum[:16])
+		}
+	case KeyOrganization:
+		if s.Organization != "" {
+			return "organization:" + s.Organization
+		}
+	}
+
+	if policyKey != KeyIP && s.UserID != 0 {
+		return "user:" + strconv.FormatInt(s.UserID, 10)
+	}
+	return "ip:" + s.IP
+}
+
+// VerifyAPIKey returns key if it is one of the known API keys, or "" so an
+// unknown key cannot pick a fresh quota.
+func VerifyAPIKey(key string, known []string) string {
+	if key == "" {
+		return ""
+	}
+	for _, candidate := range known {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+			return key
+		}
+	}
+	return ""
+}
+
+// Check applies every policy that matches a request and records the request
+// only if all of them allow it. It returns the result closest to its limit,
+// nil when no policy matches, and the policy that rejected the request, if any.
+func (l *Limiter) Check(ctx context.Context, policies Policies, method, route, role string, subject Subject) (*Result, *Policy, error) {
+	var matched []*Policy
+	var quotas []Quota
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.Matches(method, route, role) {
+			continue
+		}
+
+		matched = append(matched, policy)
+		quotas = append(quotas, Quota{
+			Key:    policy.Name + ":" + subject.Key(policy.Key),
+			Limit:  policy.Limit,
+			Window: time.Duration(policy.Window),
+		})
+	}
+
+	results, err := l.AllowAll(ctx, quotas)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tightest *Result
+	for i, result := range results {
+		if !result.Allowed {
+			return result, matched[i], nil
+		}
+		if tightest == nil || result.Remaining < tightest.Remaining {
+			tightest = result
+		}
+	}
+	return tightest, nil, nil
+}
@@ -0,0 +1,201 @@
+package rate_limiter
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps one sorted set entry per accepted request, scored
+// by its time in milliseconds. Entries older than the window are dropped before
+// counting, so the limit applies to any window-long interval and not to fixed
+// buckets. The script uses the Redis clock so replicas with skewed clocks share
+// one view of the window.
+//
+// The request is recorded in every window only if all of them have room, so a
+// request rejected by one quota does not use up the others.
+//
+// KEYS are the window keys, ARGV[1] a unique member, then the window in ms and
+// the limit of each key. Returns {allowed, remaining, reset in ms since the
+// epoch} for each key, where allowed is whether that key had room.
+var slidingWindowScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local counts = {}
+local all = true
+for i, key in ipairs(KEYS) do
+	local window = tonumber(ARGV[i * 2])
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+	counts[i] = redis.call('ZCARD', key)
+	if counts[i] >= tonumber(ARGV[i * 2 + 1]) then
+		all = false
+	end
+end
+
+local results = {}
+for i, key in ipairs(KEYS) do
+	local window = tonumber(ARGV[i * 2])
+	local limit = tonumber(ARGV[i * 2 + 1])
+	local count = counts[i]
+
+	local allowed = 0
+	if count < limit then
+		allowed = 1
+		if all then
+			redis.call('ZADD', key, now, ARGV[1])
+			count = count + 1
+		end
+	end
+	redis.call('PEXPIRE', key, window)
+
+	local reset = now + window
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	if oldest[2] then
+		reset = tonumber(oldest[2]) + window
+	end
+
+	table.insert(results, allowed)
+	table.insert(results, math.max(limit - count, 0))
+	table.insert(results, reset)
+end
+
+return results
+`)
+
+// usageScript counts the requests in the window of KEYS[1] without recording
+// one. ARGV[1] is the window in ms. Returns {count, reset in ms since the epoch}.
+var usageScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local window = tonumber(ARGV[1])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+
+local reset = now
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window
+end
+
+return {count, reset}
+`)
+
+const keyPrefix = "ratelimit:"
+
+// Result is the outcome of a rate limit check.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the oldest request in the window expires and a slot frees up.
+	Reset time.Time
+}
+
+// RetryAfter is how long a rejected client should wait before retrying.
+func (r *Result) RetryAfter(now time.Time) time.Duration {
+	if r.Allowed || !r.Reset.After(now) {
+		return 0
+	}
+	return r.Reset.Sub(now)
+}
+
+// Limiter is a sliding-window rate limiter shared by all replicas through Redis.
+type Limiter struct {
+	client *redis.Client
+	// FailOpen lets requests through when Redis is unavailable instead of rejecting them.
+	FailOpen bool
+}
+
+func NewLimiter(client *redis.Client, failOpen bool) *Limiter {
+	return &Limiter{client: client, FailOpen: failOpen}
+}
+
+// Quota is a limit of requests per window for one key.
+type Quota struct {
+	Key    string
+	Limit  int
+	Window time.Duration
+}
+
+// Allow records a request for key and reports whether it is within limit
+// requests per window.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	results, err := l.AllowAll(ctx, []Quota{{Key: key, Limit: limit, Window: window}})
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// AllowAll checks a request against every quota at once and records it only
+// when all of them allow it. It returns the result of each quota in order;
+// the request was recorded if every result is allowed.
+func (l *Limiter) AllowAll(ctx context.Context, quotas []Quota) ([]*Result, error) {
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(quotas))
+	args := []any{fmt.Sprintf("%d-%d", time.Now().UnixNano(), rand.Int63())}
+	for i, quota := range quotas {
+		keys[i] = keyPrefix + quota.Key
+		args = append(args, quota.Window.Milliseconds(), quota.Limit)
+	}
+
+	values, err := slidingWindowScript.Run(ctx, l.client, keys, args...).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, len(quotas))
+	for i, quota := range quotas {
+		results[i] = &Result{
+			Allowed:   values[i*3] == 1,
+			Limit:     quota.Limit,
+			Remaining: int(values[i*3+1]),
+			Reset:     time.UnixMilli(values[i*3+2]),
+		}
+	}
+	return results, nil
+}
+
+// Usage returns the state of key without counting a request.
+func (l *Limiter) Usage(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	values, err := usageScript.Run(ctx, l.client, []string{keyPrefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := limit - int(values[0])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &Result{
+		Allowed:   remaining > 0,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.UnixMilli(values[1]),
+	}, nil
+}
+
+// Reset clears the window of key, so its next request starts a fresh quota.
+func (l *Limiter) Reset(ctx context.Context, key string) (bool, error) {
+	deleted, err := l.client.Del(ctx, keyPrefix+key).Result()
+	return deleted > 0, err
+}
+
+// Keys returns the keys with a non-expired window that start with prefix.
+func (l *Limiter) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := l.client.Scan(ctx, 0, keyPrefix+prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val()[len(keyPrefix):])
+	}
+	return keys, iter.Err()
+}
@@ -0,0 +1,149 @@
+package rate_limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestLimiter(t *testing.T) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	mr.SetTime(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewLimiter(client, false), mr
+}
+
+func TestAllowAll(t *testing.T) {
+	tests := []struct {
+		name          string
+		quotas        []Quota
+		requests      int
+		wantAllowed   []bool
+		wantRemaining []int
+	}{
+		{
+			name:          "within every quota",
+			quotas:        []Quota{{Key: "a", Limit: 3, Window: time.Minute}, {Key: "b", Limit: 5, Window: time.Hour}},
+			requests:      2,
+			wantAllowed:   []bool{true, true},
+			wantRemaining: []int{1, 3},
+		},
+		{
+			name:          "one quota exhausted",
+			quotas:        []Quota{{Key: "a", Limit: 3, Window: time.Minute}, {Key: "b", Limit: 1, Window: time.Hour}},
+			requests:      3,
+			wantAllowed:   []bool{true, false},
+			wantRemaining: []int{2, 0},
+		},
+		{
+			name:          "every quota exhausted",
+			quotas:        []Quota{{Key: "a", Limit: 1, Window: time.Minute}, {Key: "b", Limit: 1, Window: time.Hour}},
+			requests:      2,
+			wantAllowed:   []bool{false, false},
+			wantRemaining: []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, _ := newTestLimiter(t)
+			ctx := context.Background()
+
+			var results []*Result
+			for i := 0; i < tt.requests; i++ {
+				var err error
+				if results, err = limiter.AllowAll(ctx, tt.quotas); err != nil {
+					t.Fatalf("allow: %v", err)
+				}
+			}
+
+			for i, result := range results {
+				if result.Allowed != tt.wantAllowed[i] || result.Remaining != tt.wantRemaining[i] {
+					t.Errorf("quota %s: allowed %v, remaining %d, want %v, %d", tt.quotas[i].Key, result.Allowed, result.Remaining, tt.wantAllowed[i], tt.wantRemaining[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAllowAllRecordsNothingWhenRejected(t *testing.T) {
+	limiter, _ := newTestLimiter(t)
+	ctx := context.Background()
+	quotas := []Quota{{Key: "user", Limit: 10, Window: time.Minute}, {Key: "route", Limit: 1, Window: time.Minute}}
+
+	for i := 0; i < 5; i++ {
+		if _, err := limiter.AllowAll(ctx, quotas); err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+	}
+
+	// Only the first request was recorded, the rejected ones did not use up
+	// the user quota
+	usage, err := limiter.Usage(ctx, "user", 10, time.Minute)
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	if usage.Remaining != 9 {
+		t.Errorf("user quota remaining %d, want 9", usage.Remaining)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limiter, mr := newTestLimiter(t)
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		after       time.Duration
+		wantAllowed bool
+	}{
+		{0, true},
+		{30 * time.Second, true},
+		{45 * time.Second, false},
+		// The first request left the window, the second is still in it
+		{61 * time.Second, true},
+		{62 * time.Second, false},
+		{91 * time.Second, true},
+	}
+
+	for _, step := range steps {
+		mr.SetTime(start.Add(step.after))
+		result, err := limiter.Allow(ctx, "key", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		if result.Allowed != step.wantAllowed {
+			t.Errorf("after %s: allowed %v, want %v", step.after, result.Allowed, step.wantAllowed)
+		}
+		if !result.Allowed && result.RetryAfter(start.Add(step.after)) <= 0 {
+			t.Errorf("after %s: rejected without a retry delay", step.after)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	limiter, _ := newTestLimiter(t)
+	ctx := context.Background()
+
+	if _, err := limiter.Allow(ctx, "bid-submission:user:1", 1, time.Minute); err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	keys, err := limiter.Keys(ctx, "bid-submission:")
+	if err != nil || len(keys) != 1 || keys[0] != "bid-submission:user:1" {
+		t.Fatalf("keys = %v (%v), want [bid-submission:user:1]", keys, err)
+	}
+
+	if deleted, err := limiter.Reset(ctx, "bid-submission:user:1"); err != nil || !deleted {
+		t.Fatalf("reset: deleted %v, error %v", deleted, err)
+	}
+	result, err := limiter.Allow(ctx, "bid-submission:user:1", 1, time.Minute)
+	if err != nil || !result.Allowed {
+		t.Errorf("after reset: allowed %v, error %v", result != nil && result.Allowed, err)
+	}
+}
@@ -0,0 +1,212 @@
+package rate_limiter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm/utils"
+)
+
+// What a policy counts requests by.
+const (
+	KeyUser         = "user"
+	KeyIP           = "ip"
+	KeyAPIKey       = "api_key"
+	KeyOrganization = "organization"
+)
+
+// RoleAnonymous matches requests without a valid token.
+const RoleAnonymous = "anonymous"
+
+// Duration is a time.Duration written as "1m" or "24h" in policy files and
+// in the YAML configuration.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Policy limits the requests to a set of routes. Every policy that matches a
+// request applies, so a route can have both a per-user and a per-IP quota.
+type Policy struct {
+	Name string `json:"name" yaml:"name"`
+	// Routes are "METHOD /path" or "/path" patterns in gin syntax, e.g.
+	// "POST /api/contractor/tenders/:tender_id/bid". A trailing "*" matches
+	// every route with that prefix and "*" alone matches every route.
+	Routes []string `json:"routes" yaml:"routes"`
+	// Roles the policy applies to, "client", "contractor" or "anonymous".
+	// Empty applies to everyone.
+	Roles []string `json:"roles,omitempty" yaml:"roles"`
+	// Key is what requests are counted by: user, ip, api_key or organization.
+	// Requests without the key, e.g. anonymous requests of a per-user policy,
+	// are counted by IP.
+	Key    string   `json:"key" yaml:"key"`
+	Limit  int      `json:"limit" yaml:"limit"`
+	Window Duration `json:"window" yaml:"window"`
+}
+
+// Policies is the ordered set of rate limit policies of the API.
+type Policies []Policy
+
+// DefaultPolicies are used when no policy file is configured.
+func DefaultPolicies() Policies {
+	return Policies{
+		{
+			Name:   "auth",
+			Routes: []string{"POST /login", "POST /register"},
+			Key:    KeyIP,
+			Limit:  10,
+			Window: Duration(time.Minute),
+		},
+		{
+			Name:   "bid-submission",
+			Routes: []string{"POST /api/contractor/tenders/:tender_id/bid"},
+			Roles:  []string{"contractor"},
+			Key:    KeyUser,
+			Limit:  5,
+			Window: Duration(time.Minute),
+		},
+		{
+			Name:   "anonymous",
+			Routes: []string{"*"},
+			Roles:  []string{RoleAnonymous},
+			Key:    KeyIP,
+			Limit:  300,
+			Window: Duration(time.Minute),
+		},
+		{
+			Name:   "authenticated",
+			Routes: []string{"*"},
+			Roles:  []string{"client", "contractor"},
+			Key:    KeyUser,
+			Limit:  600,
+			Window: Duration(time.Minute),
+		},
+	}
+}
+
+// LoadPolicies reads policies from a JSON file holding an array of Policy.
+func LoadPolicies(path string) (Policies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies Policies
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := policies.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return policies, nil
+}
+
+// Validate reports every invalid policy at once.
+func (p Policies) Validate() error {
+	var errs []error
+	names := make(map[string]bool)
+
+	for i, policy := range p {
+		if policy.Name == "" || strings.Contains(policy.Name, ":") {
+			errs = append(errs, fmt.Errorf("policy %d: name is required and cannot contain ':'", i))
+		} else if names[policy.Name] {
+			errs = append(errs, fmt.Errorf("policy %s: duplicate name", policy.Name))
+		}
+		names[policy.Name] = true
+
+		if len(policy.Routes) == 0 {
+			errs = append(errs, fmt.Errorf("policy %s: at least one route is required", policy.Name))
+		}
+		if !utils.Contains([]string{KeyUser, KeyIP, KeyAPIKey, KeyOrganization}, policy.Key) {
+			errs = append(errs, fmt.Errorf("policy %s: key must be user, ip, api_key or organization", policy.Name))
+		}
+		if policy.Limit <= 0 {
+			errs = append(errs, fmt.Errorf("policy %s: limit must be positive", policy.Name))
+		}
+		if policy.Window <= 0 {
+			errs = append(errs, fmt.Errorf("policy %s: window must be positive", policy.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Find returns the policy with the given name.
+func (p Policies) Find(name string) (*Policy, bool) {
+	for i := range p {
+		if p[i].Name == name {
+			return &p[i], true
+		}
+	}
+	return nil, false
+}
+
+// Matches reports whether the policy applies to a request. route is the gin
+// route pattern, empty for requests that matched no route.
+func (p *Policy) Matches(method, route, role string) bool {
+	if len(p.Roles) > 0 && !utils.Contains(p.Roles, role) {
+		return false
+	}
+
+	for _, pattern := range p.Routes {
+		if routeMatches(pattern, method, route) {
+			return true
+		}
+	}
+	return false
+}
+
+func routeMatches(pattern, method, route string) bool {
+	if patternMethod, path, ok := strings.Cut(pattern, " "); ok {
+		if !strings.EqualFold(patternMethod, method) {
+			return false
+		}
+		pattern = path
+	}
+
+	if pattern == "*" {
+		return true
+	}
+	if route == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return pattern == route
+}
@@ -0,0 +1,596 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"tender-backend/broker"
+	"tender-backend/model"
+	"tender-backend/outbox"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils"
+)
+
+// gormStore implements Store on PostgreSQL.
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGorm creates a Store on db, which may also be a transaction.
+func NewGorm(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Users() UserRepository                 { return gormUsers{s.db} }
+func (s *gormStore) Tenders() TenderRepository             { return gormTenders{s.db} }
+func (s *gormStore) Bids() BidRepository                   { return gormBids{s.db} }
+func (s *gormStore) Notifications() NotificationRepository { return gormNotifications{s.db} }
+func (s *gormStore) Webhooks() WebhookRepository           { return gormWebhooks{s.db} }
+func (s *gormStore) SavedSearches() SavedSearchRepository  { return gormSavedSearches{s.db} }
+func (s *gormStore) Reminders() ReminderRepository         { return gormReminders{s.db} }
+func (s *gormStore) Events() EventRepository               { return gormEvents{s.db} }
+
+func (s *gormStore) WithContext(ctx context.Context) Store {
+	return &gormStore{db: s.db.WithContext(ctx)}
+}
+
+func (s *gormStore) Transaction(fn func(tx Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormStore{db: tx})
+	})
+}
+
+// notFound translates the not found error of GORM.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type gormUsers struct {
+	db *gorm.DB
+}
+
+func (r gormUsers) Create(user *model.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r gormUsers) GetByID(id int64) (*model.User, error) {
+	var user model.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r gormUsers) GetByUsername(username string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r gormUsers) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r gormUsers) Delete(id int64) error {
+	return r.db.Delete(&model.User{}, id).Error
+}
+
+type gormTenders struct {
+	db *gorm.DB
+}
+
+func (r gormTenders) Create(tender *model.Tender) error {
+	return r.db.Create(tender).Error
+}
+
+func (r gormTenders) GetByID(id int64) (*model.Tender, error) {
+	var tender model.Tender
+	if err := r.db.First(&tender, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &tender, nil
+}
+
+func (r gormTenders) List() ([]model.Tender, error) {
+	var tenders []model.Tender
+	err := r.db.Find(&tenders).Error
+	return tenders, err
+}
+
+func (r gormTenders) CountByStatus(status string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Tender{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
+
+func (r gormTenders) ListOpenClosing(from, to time.Time) ([]model.Tender, error) {
+	var tenders []model.Tender
+	err := r.db.Where("status = ? AND deadline > ? AND deadline <= ?", "open", from, to).Find(&tenders).Error
+	return tenders, err
+}
+
+func (r gormTenders) UpdateStatus(tender *model.Tender, status string) error {
+	// The version check is part of the update, so a concurrent edit fails instead of being overwritten.
+	result := r.db.Model(tender).Where("version = ?", tender.Version).Updates(map[string]interface{}{
+		"status":  status,
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return r.db.First(tender, tender.ID).Error
+}
+
+func (r gormTenders) Award(tender *model.Tender, contractorID int64) error {
+	result := r.db.Model(tender).Where("version = ?", tender.Version).Updates(map[string]interface{}{
+		"status":                "awarded",
+		"awarded_contractor_id": contractorID,
+		"version":               gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return r.db.First(tender, tender.ID).Error
+}
+
+func (r gormTenders) Delete(tenderID int64, versions []int64) error {
+	query := r.db.Where("id = ?", tenderID)
+	if len(versions) > 0 {
+		query = query.Where("version IN ?", versions)
+	}
+	result := query.Delete(&model.Tender{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return r.db.Where("tender_id = ?", tenderID).Delete(&model.TenderWatch{}).Error
+}
+
+func (r gormTenders) Watch(tenderID, contractorID int64) error {
+	watch := model.TenderWatch{TenderID: tenderID, ContractorID: contractorID}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&watch).Error
+}
+
+func (r gormTenders) Unwatch(tenderID, contractorID int64) error {
+	result := r.db.Where("tender_id = ? AND contractor_id = ?", tenderID, contractorID).Delete(&model.TenderWatch{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormTenders) ListWatched(contractorID int64) ([]model.Tender, error) {
+	var tenders []model.Tender
+	err := r.db.Joins("JOIN tender_watches ON tender_watches.tender_id = tenders.id").
+		Where("tender_watches.contractor_id = ?", contractorID).
+		Order("tenders.deadline").Find(&tenders).Error
+	return tenders, err
+}
+
+func (r gormTenders) WatcherIDs(tenderID int64) ([]int64, error) {
+	var contractorIDs []int64
+	err := r.db.Model(&model.TenderWatch{}).Where("tender_id = ?", tenderID).Pluck("contractor_id", &contractorIDs).Error
+	return contractorIDs, err
+}
+
+type gormBids struct {
+	db *gorm.DB
+}
+
+func (r gormBids) Create(bid *model.Bid) error {
+	return r.db.Create(bid).Error
+}
+
+func (r gormBids) GetByID(id int64) (*model.Bid, error) {
+	var bid model.Bid
+	if err := r.db.First(&bid, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &bid, nil
+}
+
+func (r gormBids) ListByTender(tenderID int64) ([]model.Bid, error) {
+	var bids []model.Bid
+	err := r.db.Where("tender_id = ?", tenderID).Find(&bids).Error
+	return bids, err
+}
+
+func (r gormBids) ListByContractor(contractorID int64) ([]model.Bid, error) {
+	var bids []model.Bid
+	err := r.db.Where("contractor_id = ?", contractorID).Find(&bids).Error
+	return bids, err
+}
+
+func (r gormBids) BidderIDs(tenderID int64) ([]int64, error) {
+	var contractorIDs []int64
+	err := r.db.Model(&model.Bid{}).Where("tender_id = ?", tenderID).Distinct().Pluck("contractor_id", &contractorIDs).Error
+	return contractorIDs, err
+}
+
+func (r gormBids) CountCreatedSince(since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Bid{}).Where("created_at >= ?", since).Count(&count).Error
+	return count, err
+}
+
+func (r gormBids) Delete(bid *model.Bid) error {
+	result := r.db.Where("version = ?", bid.Version).Delete(bid)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+type gormNotifications struct {
+	db *gorm.DB
+}
+
+func (r gormNotifications) Create(notification *model.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r gormNotifications) GetByID(id int64) (*model.Notification, error) {
+	var notification model.Notification
+	if err := r.db.First(&notification, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &notification, nil
+}
+
+func (r gormNotifications) ListByUser(userID int64) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+func (r gormNotifications) ListAfter(userID, lastID int64) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("user_id = ? AND id > ?", userID, lastID).Order("id").Find(&notifications).Error
+	return notifications, err
+}
+
+func (r gormNotifications) ListUndelivered(userID int64) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Where("user_id = ? AND is_delivered = ?", userID, false).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r gormNotifications) ClaimFallbackDue(createdBefore, now, leaseUntil time.Time, maxAttempts int) (*model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.Raw(`UPDATE notifications SET fallback_next_at = ?
+		WHERE id = (
+			SELECT id FROM notifications
+			WHERE NOT is_delivered AND fallback_sent_at IS NULL AND created_at <= ?
+				AND fallback_attempts < ? AND (fallback_next_at IS NULL OR fallback_next_at <= ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, createdBefore, maxAttempts, now).
+		Scan(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(notifications) == 0 {
+		return nil, ErrNotFound
+	}
+	return &notifications[0], nil
+}
+
+func (r gormNotifications) MarkDelivered(id int64, at time.Time) error {
+	return r.db.Model(&model.Notification{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_delivered": true,
+		"delivered_at": at,
+	}).Error
+}
+
+func (r gormNotifications) MarkFallbackSent(id int64, at time.Time) error {
+	return r.db.Model(&model.Notification{}).Where("id = ?", id).Update("fallback_sent_at", at).Error
+}
+
+func (r gormNotifications) RetryFallback(id int64, attempts int, at time.Time) error {
+	return r.db.Model(&model.Notification{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"fallback_attempts": attempts,
+		"fallback_next_at":  at,
+	}).Error
+}
+
+func (r gormNotifications) GetPreferences(userID int64) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+func (r gormNotifications) GetPreference(userID int64, eventType string) (*model.NotificationPreference, error) {
+	// Find instead of First, as a missing preference is common and not worth logging.
+	var preference model.NotificationPreference
+	result := r.db.Where("user_id = ? AND event_type = ?", userID, eventType).Limit(1).Find(&preference)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return &preference, nil
+}
+
+func (r gormNotifications) SavePreference(preference *model.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channels"}),
+	}).Create(preference).Error
+}
+
+func (r gormNotifications) GetSettings(userID int64) (*model.NotificationSettings, error) {
+	settings := model.NotificationSettings{UserID: userID}
+	err := r.db.Where("user_id = ?", userID).Limit(1).Find(&settings).Error
+	return &settings, err
+}
+
+func (r gormNotifications) SaveSettings(settings *model.NotificationSettings) error {
+	return r.db.Save(settings).Error
+}
+
+type gormWebhooks struct {
+	db *gorm.DB
+}
+
+func (r gormWebhooks) CreateEndpoint(endpoint *model.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+func (r gormWebhooks) GetEndpoint(id int64) (*model.WebhookEndpoint, error) {
+	var endpoint model.WebhookEndpoint
+	if err := r.db.First(&endpoint, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &endpoint, nil
+}
+
+func (r gormWebhooks) ListEndpoints(userID int64) ([]model.WebhookEndpoint, error) {
+	var endpoints []model.WebhookEndpoint
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (r gormWebhooks) UpdateEndpointState(endpoint *model.WebhookEndpoint) error {
+	return r.db.Model(endpoint).Select("is_active", "consecutive_failures", "disabled_at").Updates(endpoint).Error
+}
+
+func (r gormWebhooks) RecordFailure(id int64, maxFailures int, now time.Time) (*model.WebhookEndpoint, error) {
+	// The SET expressions see the row as it was before the update.
+	var endpoints []model.WebhookEndpoint
+	err := r.db.Raw(`UPDATE webhook_endpoints SET
+			consecutive_failures = consecutive_failures + 1,
+			is_active = is_active AND consecutive_failures + 1 < ?,
+			disabled_at = CASE WHEN is_active AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END
+		WHERE id = ?
+		RETURNING *`, maxFailures, maxFailures, now, id).
+		Scan(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNotFound
+	}
+	return &endpoints[0], nil
+}
+
+func (r gormWebhooks) ResetFailures(id int64) error {
+	return r.db.Model(&model.WebhookEndpoint{}).
+		Where("id = ? AND is_active AND consecutive_failures > 0", id).
+		Update("consecutive_failures", 0).Error
+}
+
+func (r gormWebhooks) DeleteEndpoint(id int64) error {
+	if err := r.db.Where("endpoint_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&model.WebhookEndpoint{}, id).Error
+}
+
+func (r gormWebhooks) CreateDelivery(delivery *model.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r gormWebhooks) GetDelivery(id int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &delivery, nil
+}
+
+func (r gormWebhooks) ListDeliveries(endpointID int64) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.Where("endpoint_id = ?", endpointID).Order("id DESC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r gormWebhooks) ClaimDue(now, leaseUntil time.Time) (*model.WebhookDelivery, error) {
+	// SKIP LOCKED lets concurrent workers claim different deliveries instead
+	// of waiting for each other.
+	var deliveries []model.WebhookDelivery
+	err := r.db.Raw(`UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id = (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_endpoints e ON e.id = d.endpoint_id
+			WHERE d.status = ? AND d.next_attempt_at <= ? AND e.is_active
+			ORDER BY d.id
+			LIMIT 1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING *`, leaseUntil, model.WebhookStatusPending, now).
+		Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, ErrNotFound
+	}
+	return &deliveries[0], nil
+}
+
+func (r gormWebhooks) UpdateDelivery(delivery *model.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+type gormSavedSearches struct {
+	db *gorm.DB
+}
+
+func (r gormSavedSearches) Create(search *model.SavedSearch) error {
+	return r.db.Create(search).Error
+}
+
+func (r gormSavedSearches) GetByID(id int64) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	if err := r.db.First(&search, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &search, nil
+}
+
+func (r gormSavedSearches) ListByContractor(contractorID int64) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("contractor_id = ?", contractorID).Order("id").Find(&searches).Error
+	return searches, err
+}
+
+func (r gormSavedSearches) ListByBudget(budget float64) ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("(min_budget IS NULL OR min_budget <= ?) AND (max_budget IS NULL OR max_budget >= ?)",
+		budget, budget).Order("id").Find(&searches).Error
+	return searches, err
+}
+
+func (r gormSavedSearches) ListWithDigest() ([]model.SavedSearch, error) {
+	var searches []model.SavedSearch
+	err := r.db.Where("digest <> ?", model.DigestNone).Order("id").Find(&searches).Error
+	return searches, err
+}
+
+func (r gormSavedSearches) Delete(id int64) error {
+	if err := r.db.Where("saved_search_id = ?", id).Delete(&model.SavedSearchMatch{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&model.SavedSearch{}, id).Error
+}
+
+func (r gormSavedSearches) AddMatch(searchID, tenderID int64) (bool, error) {
+	match := model.SavedSearchMatch{SavedSearchID: searchID, TenderID: tenderID}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&match)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r gormSavedSearches) ListUndigested(searchID int64) ([]model.Tender, error) {
+	var tenders []model.Tender
+	err := r.db.Joins("JOIN saved_search_matches ON saved_search_matches.tender_id = tenders.id").
+		Where("saved_search_matches.saved_search_id = ? AND saved_search_matches.digested_at IS NULL", searchID).
+		Order("tenders.id").Find(&tenders).Error
+	return tenders, err
+}
+
+func (r gormSavedSearches) ClaimDigest(search *model.SavedSearch, at time.Time) (bool, error) {
+	query := r.db.Model(&model.SavedSearch{}).Where("id = ?", search.ID)
+	if search.LastDigestAt == nil {
+		query = query.Where("last_digest_at IS NULL")
+	} else {
+		query = query.Where("last_digest_at = ?", *search.LastDigestAt)
+	}
+
+	result := query.Update("last_digest_at", at)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	search.LastDigestAt = &at
+	return true, nil
+}
+
+func (r gormSavedSearches) MarkDigested(searchID int64, tenderIDs []int64, at time.Time) error {
+	if len(tenderIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.SavedSearchMatch{}).
+		Where("saved_search_id = ? AND tender_id IN ?", searchID, tenderIDs).
+		Update("digested_at", at).Error
+}
+
+type gormReminders struct {
+	db *gorm.DB
+}
+
+func (r gormReminders) ListByTenders(tenderIDs []int64) ([]model.DeadlineReminder, error) {
+	var reminders []model.DeadlineReminder
+	err := r.db.Where("tender_id IN ?", tenderIDs).Find(&reminders).Error
+	return reminders, err
+}
+
+func (r gormReminders) Create(reminder *model.DeadlineReminder) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+	return result.RowsAffected > 0, result.Error
+}
+
+type gormEvents struct {
+	db *gorm.DB
+}
+
+func (r gormEvents) WriteOutbox(aggregateType string, aggregateID int64, topic string, msg broker.Message) error {
+	return outbox.Write(r.db, aggregateType, aggregateID, topic, msg)
+}
+
+func (r gormEvents) QueueWebhooks(eventID, eventType string, payload []byte, recipients []int64) error {
+	query := r.db.Where("is_active = ?", true)
+	if len(recipients) > 0 {
+		query = query.Where("user_id IN ?", recipients)
+	}
+
+	var endpoints []model.WebhookEndpoint
+	if err := query.Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	var deliveries []model.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !utils.Contains(strings.Split(endpoint.EventTypes, ","), eventType) {
+			continue
+		}
+
+		deliveries = append(deliveries, model.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        model.WebhookStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return r.db.Create(&deliveries).Error
+}
@@ -0,0 +1,1074 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"tender-backend/broker"
+	"tender-backend/model"
+	"time"
+)
+
+// errDuplicate mirrors a unique constraint violation of the database.
+var errDuplicate = errors.New("duplicate key value violates unique constraint")
+
+// OutboxMessage is a message written to the outbox of a Memory store.
+type OutboxMessage struct {
+	AggregateType string
+	AggregateID   int64
+	Topic         string
+	Message       broker.Message
+}
+
+// QueuedWebhook is an event queued for webhook delivery in a Memory store.
+type QueuedWebhook struct {
+	EventID    string
+	EventType  string
+	Payload    []byte
+	Recipients []int64
+}
+
+// Memory implements Store in memory, so the business rules of the services
+// can be tested without PostgreSQL. Operations run one at a time, and the
+// writes of a transaction are rolled back when it fails.
+type Memory struct {
+	state *memoryState
+	// inTx is set on the Store passed to a transaction, which already holds txMu.
+	inTx bool
+}
+
+type memoryState struct {
+	// txMu is held for a whole transaction, or for a single operation outside one.
+	txMu sync.Mutex
+	mu   sync.Mutex
+	data memoryData
+}
+
+type watchKey struct {
+	tenderID     int64
+	contractorID int64
+}
+
+type preferenceKey struct {
+	userID    int64
+	eventType string
+}
+
+type reminderKey struct {
+	tenderID      int64
+	offsetMinutes int
+}
+
+type matchKey struct {
+	searchID int64
+	tenderID int64
+}
+
+type memoryData struct {
+	lastID        int64
+	users         map[int64]model.User
+	tenders       map[int64]model.Tender
+	bids          map[int64]model.Bid
+	watches       map[watchKey]model.TenderWatch
+	notifications map[int64]model.Notification
+	preferences   map[preferenceKey]model.NotificationPreference
+	settings      map[int64]model.NotificationSettings
+	endpoints     map[int64]model.WebhookEndpoint
+	deliveries    map[int64]model.WebhookDelivery
+	savedSearches map[int64]model.SavedSearch
+	matches       map[matchKey]model.SavedSearchMatch
+	reminders     map[reminderKey]model.DeadlineReminder
+	outbox        []OutboxMessage
+	webhooks      []QueuedWebhook
+}
+
+func NewMemory() *Memory {
+	return &Memory{state: &memoryState{data: memoryData{
+		users:         make(map[int64]model.User),
+		tenders:       make(map[int64]model.Tender),
+		bids:          make(map[int64]model.Bid),
+		watches:       make(map[watchKey]model.TenderWatch),
+		notifications: make(map[int64]model.Notification),
+		preferences:   make(map[preferenceKey]model.NotificationPreference),
+		settings:      make(map[int64]model.NotificationSettings),
+		endpoints:     make(map[int64]model.WebhookEndpoint),
+		deliveries:    make(map[int64]model.WebhookDelivery),
+		savedSearches: make(map[int64]model.SavedSearch),
+		matches:       make(map[matchKey]model.SavedSearchMatch),
+		reminders:     make(map[reminderKey]model.DeadlineReminder),
+	}}}
+}
+
+func (m *Memory) Users() UserRepository                 { return memoryUsers{m} }
+func (m *Memory) Tenders() TenderRepository             { return memoryTenders{m} }
+func (m *Memory) Bids() BidRepository                   { return memoryBids{m} }
+func (m *Memory) Notifications() NotificationRepository { return memoryNotifications{m} }
+func (m *Memory) Webhooks() WebhookRepository           { return memoryWebhooks{m} }
+func (m *Memory) SavedSearches() SavedSearchRepository  { return memorySavedSearches{m} }
+func (m *Memory) Reminders() ReminderRepository         { return memoryReminders{m} }
+func (m *Memory) Events() EventRepository               { return memoryEvents{m} }
+
+func (m *Memory) WithContext(context.Context) Store {
+	return m
+}
+
+func (m *Memory) Transaction(fn func(tx Store) error) error {
+	if m.inTx {
+		return fn(m)
+	}
+
+	m.state.txMu.Lock()
+	defer m.state.txMu.Unlock()
+
+	m.state.mu.Lock()
+	snapshot := m.state.data.clone()
+	m.state.mu.Unlock()
+
+	if err := fn(&Memory{state: m.state, inTx: true}); err != nil {
+		m.state.mu.Lock()
+		m.state.data = snapshot
+		m.state.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Outbox returns the messages written to the outbox, oldest first.
+func (m *Memory) Outbox() []OutboxMessage {
+	data := m.lock()
+	defer m.unlock()
+
+	return append([]OutboxMessage(nil), data.outbox...)
+}
+
+// QueuedWebhooks returns the events queued for webhook delivery, oldest first.
+func (m *Memory) QueuedWebhooks() []QueuedWebhook {
+	data := m.lock()
+	defer m.unlock()
+
+	return append([]QueuedWebhook(nil), data.webhooks...)
+}
+
+func (m *Memory) lock() *memoryData {
+	if !m.inTx {
+		m.state.txMu.Lock()
+	}
+	m.state.mu.Lock()
+	return &m.state.data
+}
+
+func (m *Memory) unlock() {
+	m.state.mu.Unlock()
+	if !m.inTx {
+		m.state.txMu.Unlock()
+	}
+}
+
+func (d *memoryData) nextID() int64 {
+	d.lastID++
+	return d.lastID
+}
+
+func (d *memoryData) clone() memoryData {
+	c := *d
+	c.users = cloneMap(d.users)
+	c.tenders = cloneMap(d.tenders)
+	c.bids = cloneMap(d.bids)
+	c.watches = cloneMap(d.watches)
+	c.notifications = cloneMap(d.notifications)
+	c.preferences = cloneMap(d.preferences)
+	c.settings = cloneMap(d.settings)
+	c.endpoints = cloneMap(d.endpoints)
+	c.deliveries = cloneMap(d.deliveries)
+	c.savedSearches = cloneMap(d.savedSearches)
+	c.matches = cloneMap(d.matches)
+	c.reminders = cloneMap(d.reminders)
+	c.outbox = append([]OutboxMessage(nil), d.outbox...)
+	c.webhooks = append([]QueuedWebhook(nil), d.webhooks...)
+	return c
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// sortedByID returns the values matching keep, ordered by ID like the database.
+func sortedByID[V any](m map[int64]V, keep func(V) bool) []V {
+	var ids []int64
+	for id, v := range m {
+		if keep(v) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	values := make([]V, len(ids))
+	for i, id := range ids {
+		values[i] = m[id]
+	}
+	return values
+}
+
+type memoryUsers struct {
+	m *Memory
+}
+
+func (r memoryUsers) Create(user *model.User) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	for _, existing := range data.users {
+		if existing.Email == user.Email || existing.Username == user.Username {
+			return errDuplicate
+		}
+	}
+
+	user.ID = data.nextID()
+	if user.Locale == "" {
+		user.Locale = "en"
+	}
+	data.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) GetByID(id int64) (*model.User, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	user, ok := data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r memoryUsers) GetByUsername(username string) (*model.User, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	for _, user := range data.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryUsers) Update(user *model.User) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) Delete(id int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	delete(data.users, id)
+	return nil
+}
+
+type memoryTenders struct {
+	m *Memory
+}
+
+func (r memoryTenders) Create(tender *model.Tender) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	tender.ID = data.nextID()
+	if tender.Version == 0 {
+		tender.Version = 1
+	}
+	if tender.UpdatedAt.IsZero() {
+		tender.UpdatedAt = time.Now()
+	}
+	data.tenders[tender.ID] = *tender
+	return nil
+}
+
+func (r memoryTenders) GetByID(id int64) (*model.Tender, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	tender, ok := data.tenders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &tender, nil
+}
+
+func (r memoryTenders) List() ([]model.Tender, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.tenders, func(model.Tender) bool { return true }), nil
+}
+
+func (r memoryTenders) CountByStatus(status string) (int64, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var count int64
+	for _, tender := range data.tenders {
+		if tender.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryTenders) ListOpenClosing(from, to time.Time) ([]model.Tender, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.tenders, func(t model.Tender) bool {
+		return t.Status == "open" && t.Deadline.After(from) && !t.Deadline.After(to)
+	}), nil
+}
+
+func (r memoryTenders) UpdateStatus(tender *model.Tender, status string) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	stored, ok := data.tenders[tender.ID]
+	if !ok || stored.Version != tender.Version {
+		return ErrConflict
+	}
+
+	stored.Status = status
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	data.tenders[tender.ID] = stored
+	*tender = stored
+	return nil
+}
+
+func (r memoryTenders) Award(tender *model.Tender, contractorID int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	stored, ok := data.tenders[tender.ID]
+	if !ok || stored.Version != tender.Version {
+		return ErrConflict
+	}
+
+	stored.Status = "awarded"
+	stored.AwardedContractorID = contractorID
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	data.tenders[tender.ID] = stored
+	*tender = stored
+	return nil
+}
+
+func (r memoryTenders) Delete(tenderID int64, versions []int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	tender, ok := data.tenders[tenderID]
+	if !ok || !containsVersion(versions, tender.Version) {
+		return ErrConflict
+	}
+
+	delete(data.tenders, tenderID)
+	for key := range data.watches {
+		if key.tenderID == tenderID {
+			delete(data.watches, key)
+		}
+	}
+	return nil
+}
+
+func containsVersion(versions []int64, version int64) bool {
+	if len(versions) == 0 {
+		return true
+	}
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (r memoryTenders) Watch(tenderID, contractorID int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	key := watchKey{tenderID: tenderID, contractorID: contractorID}
+	if _, ok := data.watches[key]; !ok {
+		data.watches[key] = model.TenderWatch{
+			ID:           data.nextID(),
+			TenderID:     tenderID,
+			ContractorID: contractorID,
+			CreatedAt:    time.Now(),
+		}
+	}
+	return nil
+}
+
+func (r memoryTenders) Unwatch(tenderID, contractorID int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	key := watchKey{tenderID: tenderID, contractorID: contractorID}
+	if _, ok := data.watches[key]; !ok {
+		return ErrNotFound
+	}
+	delete(data.watches, key)
+	return nil
+}
+
+func (r memoryTenders) ListWatched(contractorID int64) ([]model.Tender, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var tenders []model.Tender
+	for key := range data.watches {
+		if tender, ok := data.tenders[key.tenderID]; ok && key.contractorID == contractorID {
+			tenders = append(tenders, tender)
+		}
+	}
+	sort.Slice(tenders, func(i, j int) bool { return tenders[i].Deadline.Before(tenders[j].Deadline) })
+	return tenders, nil
+}
+
+func (r memoryTenders) WatcherIDs(tenderID int64) ([]int64, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var contractorIDs []int64
+	for key := range data.watches {
+		if key.tenderID == tenderID {
+			contractorIDs = append(contractorIDs, key.contractorID)
+		}
+	}
+	sort.Slice(contractorIDs, func(i, j int) bool { return contractorIDs[i] < contractorIDs[j] })
+	return contractorIDs, nil
+}
+
+type memoryBids struct {
+	m *Memory
+}
+
+func (r memoryBids) Create(bid *model.Bid) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	bid.ID = data.nextID()
+	if bid.Version == 0 {
+		bid.Version = 1
+	}
+	if bid.CreatedAt.IsZero() {
+		bid.CreatedAt = time.Now()
+	}
+	if bid.UpdatedAt.IsZero() {
+		bid.UpdatedAt = bid.CreatedAt
+	}
+	data.bids[bid.ID] = *bid
+	return nil
+}
+
+func (r memoryBids) GetByID(id int64) (*model.Bid, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	bid, ok := data.bids[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &bid, nil
+}
+
+func (r memoryBids) ListByTender(tenderID int64) ([]model.Bid, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.bids, func(bid model.Bid) bool { return bid.TenderID == tenderID }), nil
+}
+
+func (r memoryBids) ListByContractor(contractorID int64) ([]model.Bid, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.bids, func(bid model.Bid) bool { return bid.ContractorID == contractorID }), nil
+}
+
+func (r memoryBids) BidderIDs(tenderID int64) ([]int64, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	seen := make(map[int64]bool)
+	var contractorIDs []int64
+	for _, bid := range sortedByID(data.bids, func(bid model.Bid) bool { return bid.TenderID == tenderID }) {
+		if !seen[bid.ContractorID] {
+			seen[bid.ContractorID] = true
+			contractorIDs = append(contractorIDs, bid.ContractorID)
+		}
+	}
+	return contractorIDs, nil
+}
+
+func (r memoryBids) CountCreatedSince(since time.Time) (int64, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var count int64
+	for _, bid := range data.bids {
+		if !bid.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryBids) Delete(bid *model.Bid) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	stored, ok := data.bids[bid.ID]
+	if !ok || stored.Version != bid.Version {
+		return ErrConflict
+	}
+	delete(data.bids, bid.ID)
+	return nil
+}
+
+type memoryNotifications struct {
+	m *Memory
+}
+
+func (r memoryNotifications) Create(notification *model.Notification) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notification.ID = data.nextID()
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+	data.notifications[notification.ID] = *notification
+	return nil
+}
+
+func (r memoryNotifications) GetByID(id int64) (*model.Notification, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notification, ok := data.notifications[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &notification, nil
+}
+
+func (r memoryNotifications) ListByUser(userID int64) ([]model.Notification, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notifications := sortedByID(data.notifications, func(n model.Notification) bool { return n.UserID == userID })
+	for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+		notifications[i], notifications[j] = notifications[j], notifications[i]
+	}
+	return notifications, nil
+}
+
+func (r memoryNotifications) ListAfter(userID, lastID int64) ([]model.Notification, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.notifications, func(n model.Notification) bool { return n.UserID == userID && n.ID > lastID }), nil
+}
+
+func (r memoryNotifications) ListUndelivered(userID int64) ([]model.Notification, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.notifications, func(n model.Notification) bool { return n.UserID == userID && !n.IsDelivered }), nil
+}
+
+func (r memoryNotifications) ClaimFallbackDue(createdBefore, now, leaseUntil time.Time, maxAttempts int) (*model.Notification, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notifications := sortedByID(data.notifications, func(n model.Notification) bool {
+		return !n.IsDelivered && n.FallbackSentAt == nil && !n.CreatedAt.After(createdBefore) &&
+			n.FallbackAttempts < maxAttempts && (n.FallbackNextAt == nil || !n.FallbackNextAt.After(now))
+	})
+	if len(notifications) == 0 {
+		return nil, ErrNotFound
+	}
+
+	notification := notifications[0]
+	notification.FallbackNextAt = &leaseUntil
+	data.notifications[notification.ID] = notification
+	return &notification, nil
+}
+
+func (r memoryNotifications) MarkDelivered(id int64, at time.Time) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notification, ok := data.notifications[id]
+	if !ok {
+		return nil
+	}
+	notification.IsDelivered = true
+	notification.DeliveredAt = &at
+	data.notifications[id] = notification
+	return nil
+}
+
+func (r memoryNotifications) MarkFallbackSent(id int64, at time.Time) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notification, ok := data.notifications[id]
+	if !ok {
+		return nil
+	}
+	notification.FallbackSentAt = &at
+	data.notifications[id] = notification
+	return nil
+}
+
+func (r memoryNotifications) RetryFallback(id int64, attempts int, at time.Time) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	notification, ok := data.notifications[id]
+	if !ok {
+		return nil
+	}
+	notification.FallbackAttempts = attempts
+	notification.FallbackNextAt = &at
+	data.notifications[id] = notification
+	return nil
+}
+
+func (r memoryNotifications) GetPreferences(userID int64) ([]model.NotificationPreference, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var preferences []model.NotificationPreference
+	for key, preference := range data.preferences {
+		if key.userID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	sort.Slice(preferences, func(i, j int) bool { return preferences[i].ID < preferences[j].ID })
+	return preferences, nil
+}
+
+func (r memoryNotifications) GetPreference(userID int64, eventType string) (*model.NotificationPreference, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	preference, ok := data.preferences[preferenceKey{userID: userID, eventType: eventType}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &preference, nil
+}
+
+func (r memoryNotifications) SavePreference(preference *model.NotificationPreference) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	key := preferenceKey{userID: preference.UserID, eventType: preference.EventType}
+	if existing, ok := data.preferences[key]; ok {
+		preference.ID = existing.ID
+	} else {
+		preference.ID = data.nextID()
+	}
+	data.preferences[key] = *preference
+	return nil
+}
+
+func (r memoryNotifications) GetSettings(userID int64) (*model.NotificationSettings, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	settings, ok := data.settings[userID]
+	if !ok {
+		settings = model.NotificationSettings{UserID: userID}
+	}
+	return &settings, nil
+}
+
+func (r memoryNotifications) SaveSettings(settings *model.NotificationSettings) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.settings[settings.UserID] = *settings
+	return nil
+}
+
+type memoryWebhooks struct {
+	m *Memory
+}
+
+func (r memoryWebhooks) CreateEndpoint(endpoint *model.WebhookEndpoint) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	endpoint.ID = data.nextID()
+	if endpoint.CreatedAt.IsZero() {
+		endpoint.CreatedAt = time.Now()
+	}
+	data.endpoints[endpoint.ID] = *endpoint
+	return nil
+}
+
+func (r memoryWebhooks) GetEndpoint(id int64) (*model.WebhookEndpoint, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	endpoint, ok := data.endpoints[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &endpoint, nil
+}
+
+func (r memoryWebhooks) ListEndpoints(userID int64) ([]model.WebhookEndpoint, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.endpoints, func(e model.WebhookEndpoint) bool { return e.UserID == userID }), nil
+}
+
+func (r memoryWebhooks) UpdateEndpointState(endpoint *model.WebhookEndpoint) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	stored, ok := data.endpoints[endpoint.ID]
+	if !ok {
+		return nil
+	}
+	stored.IsActive = endpoint.IsActive
+	stored.ConsecutiveFailures = endpoint.ConsecutiveFailures
+	stored.DisabledAt = endpoint.DisabledAt
+	data.endpoints[endpoint.ID] = stored
+	return nil
+}
+
+func (r memoryWebhooks) RecordFailure(id int64, maxFailures int, now time.Time) (*model.WebhookEndpoint, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	endpoint, ok := data.endpoints[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	endpoint.ConsecutiveFailures++
+	if endpoint.IsActive && endpoint.ConsecutiveFailures >= maxFailures {
+		endpoint.IsActive = false
+		endpoint.DisabledAt = &now
+	}
+	data.endpoints[id] = endpoint
+	return &endpoint, nil
+}
+
+func (r memoryWebhooks) ResetFailures(id int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	endpoint, ok := data.endpoints[id]
+	if !ok || !endpoint.IsActive {
+		return nil
+	}
+	endpoint.ConsecutiveFailures = 0
+	data.endpoints[id] = endpoint
+	return nil
+}
+
+func (r memoryWebhooks) DeleteEndpoint(id int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	for deliveryID, delivery := range data.deliveries {
+		if delivery.EndpointID == id {
+			delete(data.deliveries, deliveryID)
+		}
+	}
+	delete(data.endpoints, id)
+	return nil
+}
+
+func (r memoryWebhooks) CreateDelivery(delivery *model.WebhookDelivery) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.createDelivery(delivery)
+	return nil
+}
+
+func (d *memoryData) createDelivery(delivery *model.WebhookDelivery) {
+	delivery.ID = d.nextID()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	d.deliveries[delivery.ID] = *delivery
+}
+
+func (r memoryWebhooks) GetDelivery(id int64) (*model.WebhookDelivery, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	delivery, ok := data.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r memoryWebhooks) ListDeliveries(endpointID int64) ([]model.WebhookDelivery, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	deliveries := sortedByID(data.deliveries, func(d model.WebhookDelivery) bool { return d.EndpointID == endpointID })
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	return deliveries, nil
+}
+
+func (r memoryWebhooks) ClaimDue(now, leaseUntil time.Time) (*model.WebhookDelivery, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	deliveries := sortedByID(data.deliveries, func(d model.WebhookDelivery) bool {
+		return d.Status == model.WebhookStatusPending && !d.NextAttemptAt.After(now) && data.endpoints[d.EndpointID].IsActive
+	})
+	if len(deliveries) == 0 {
+		return nil, ErrNotFound
+	}
+
+	delivery := deliveries[0]
+	delivery.NextAttemptAt = leaseUntil
+	data.deliveries[delivery.ID] = delivery
+	return &delivery, nil
+}
+
+func (r memoryWebhooks) UpdateDelivery(delivery *model.WebhookDelivery) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+type memorySavedSearches struct {
+	m *Memory
+}
+
+func (r memorySavedSearches) Create(search *model.SavedSearch) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	search.ID = data.nextID()
+	if search.CreatedAt.IsZero() {
+		search.CreatedAt = time.Now()
+	}
+	data.savedSearches[search.ID] = *search
+	return nil
+}
+
+func (r memorySavedSearches) GetByID(id int64) (*model.SavedSearch, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	search, ok := data.savedSearches[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &search, nil
+}
+
+func (r memorySavedSearches) ListByContractor(contractorID int64) ([]model.SavedSearch, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.savedSearches, func(s model.SavedSearch) bool { return s.ContractorID == contractorID }), nil
+}
+
+func (r memorySavedSearches) ListByBudget(budget float64) ([]model.SavedSearch, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.savedSearches, func(s model.SavedSearch) bool {
+		return (s.MinBudget == nil || *s.MinBudget <= budget) && (s.MaxBudget == nil || *s.MaxBudget >= budget)
+	}), nil
+}
+
+func (r memorySavedSearches) ListWithDigest() ([]model.SavedSearch, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.savedSearches, func(s model.SavedSearch) bool { return s.Digest != model.DigestNone }), nil
+}
+
+func (r memorySavedSearches) Delete(id int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	for key := range data.matches {
+		if key.searchID == id {
+			delete(data.matches, key)
+		}
+	}
+	delete(data.savedSearches, id)
+	return nil
+}
+
+func (r memorySavedSearches) AddMatch(searchID, tenderID int64) (bool, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	key := matchKey{searchID: searchID, tenderID: tenderID}
+	if _, ok := data.matches[key]; ok {
+		return false, nil
+	}
+	data.matches[key] = model.SavedSearchMatch{
+		ID:            data.nextID(),
+		SavedSearchID: searchID,
+		TenderID:      tenderID,
+		CreatedAt:     time.Now(),
+	}
+	return true, nil
+}
+
+func (r memorySavedSearches) ListUndigested(searchID int64) ([]model.Tender, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	return sortedByID(data.tenders, func(t model.Tender) bool {
+		match, ok := data.matches[matchKey{searchID: searchID, tenderID: t.ID}]
+		return ok && match.DigestedAt == nil
+	}), nil
+}
+
+func (r memorySavedSearches) ClaimDigest(search *model.SavedSearch, at time.Time) (bool, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	stored, ok := data.savedSearches[search.ID]
+	if !ok {
+		return false, nil
+	}
+	if (stored.LastDigestAt == nil) != (search.LastDigestAt == nil) ||
+		stored.LastDigestAt != nil && !stored.LastDigestAt.Equal(*search.LastDigestAt) {
+		return false, nil
+	}
+
+	stored.LastDigestAt = &at
+	data.savedSearches[search.ID] = stored
+	search.LastDigestAt = &at
+	return true, nil
+}
+
+func (r memorySavedSearches) MarkDigested(searchID int64, tenderIDs []int64, at time.Time) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	for _, tenderID := range tenderIDs {
+		key := matchKey{searchID: searchID, tenderID: tenderID}
+		if match, ok := data.matches[key]; ok {
+			match.DigestedAt = &at
+			data.matches[key] = match
+		}
+	}
+	return nil
+}
+
+type memoryReminders struct {
+	m *Memory
+}
+
+func (r memoryReminders) ListByTenders(tenderIDs []int64) ([]model.DeadlineReminder, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	var reminders []model.DeadlineReminder
+	for key, reminder := range data.reminders {
+		if slices.Contains(tenderIDs, key.tenderID) {
+			reminders = append(reminders, reminder)
+		}
+	}
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].ID < reminders[j].ID })
+	return reminders, nil
+}
+
+func (r memoryReminders) Create(reminder *model.DeadlineReminder) (bool, error) {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	key := reminderKey{tenderID: reminder.TenderID, offsetMinutes: reminder.OffsetMinutes}
+	if _, ok := data.reminders[key]; ok {
+		return false, nil
+	}
+
+	reminder.ID = data.nextID()
+	if reminder.CreatedAt.IsZero() {
+		reminder.CreatedAt = time.Now()
+	}
+	data.reminders[key] = *reminder
+	return true, nil
+}
+
+type memoryEvents struct {
+	m *Memory
+}
+
+func (r memoryEvents) WriteOutbox(aggregateType string, aggregateID int64, topic string, msg broker.Message) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.outbox = append(data.outbox, OutboxMessage{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Topic:         topic,
+		Message:       msg,
+	})
+	return nil
+}
+
+func (r memoryEvents) QueueWebhooks(eventID, eventType string, payload []byte, recipients []int64) error {
+	data := r.m.lock()
+	defer r.m.unlock()
+
+	data.webhooks = append(data.webhooks, QueuedWebhook{
+		EventID:    eventID,
+		EventType:  eventType,
+		Payload:    payload,
+		Recipients: recipients,
+	})
+
+	for _, endpoint := range sortedByID(data.endpoints, func(e model.WebhookEndpoint) bool {
+		return e.IsActive && (len(recipients) == 0 || slices.Contains(recipients, e.UserID))
+	}) {
+		if !slices.Contains(strings.Split(endpoint.EventTypes, ","), eventType) {
+			continue
+		}
+
+		data.createDelivery(&model.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        model.WebhookStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tender-backend/broker"
+	"tender-backend/model"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned by conditional writes when the record changed
+	// since it was read, i.e. its version no longer matches.
+	ErrConflict = errors.New("record was modified")
+)
+
+// Store gives access to every repository. The repositories of the Store passed
+// to a Transaction callback write in that transaction.
+type Store interface {
+	Users() UserRepository
+	Tenders() TenderRepository
+	Bids() BidRepository
+	Notifications() NotificationRepository
+	Webhooks() WebhookRepository
+	SavedSearches() SavedSearchRepository
+	Reminders() ReminderRepository
+	Events() EventRepository
+	// WithContext returns a Store whose operations use ctx, which also
+	// carries the request ID into the query logs.
+	WithContext(ctx context.Context) Store
+	// Transaction runs fn in a transaction that commits when fn returns nil
+	// and rolls back otherwise. Transactions started within fn join it.
+	Transaction(fn func(tx Store) error) error
+}
+
+type UserRepository interface {
+	Create(user *model.User) error
+	GetByID(id int64) (*model.User, error)
+	GetByUsername(username string) (*model.User, error)
+	Update(user *model.User) error
+	Delete(id int64) error
+}
+
+type TenderRepository interface {
+	Create(tender *model.Tender) error
+	GetByID(id int64) (*model.Tender, error)
+	List() ([]model.Tender, error)
+	CountByStatus(status string) (int64, error)
+	// ListOpenClosing returns the open tenders whose deadline is after from
+	// and not after to.
+	ListOpenClosing(from, to time.Time) ([]model.Tender, error)
+	// UpdateStatus sets the status of the tender and increments its version,
+	// provided its version is still tender.Version, and reloads tender.
+	UpdateStatus(tender *model.Tender, status string) error
+	// Award marks the tender awarded to the contractor and increments its
+	// version, provided its version is still tender.Version, and reloads tender.
+	Award(tender *model.Tender, contractorID int64) error
+	// Delete deletes the tender and its watches, provided its version is one
+	// of versions; empty versions match any version.
+	Delete(tenderID int64, versions []int64) error
+	// Watch subscribes a contractor to a tender. Watching twice is not an error.
+	Watch(tenderID, contractorID int64) error
+	Unwatch(tenderID, contractorID int64) error
+	// ListWatched returns the tenders a contractor watches, by deadline.
+	ListWatched(contractorID int64) ([]model.Tender, error)
+	// WatcherIDs returns the contractors watching a tender.
+	WatcherIDs(tenderID int64) ([]int64, error)
+}
+
+type BidRepository interface {
+	Create(bid *model.Bid) error
+	GetByID(id int64) (*model.Bid, error)
+	ListByTender(tenderID int64) ([]model.Bid, error)
+	ListByContractor(contractorID int64) ([]model.Bid, error)
+	// BidderIDs returns the distinct contractors who bid on a tender.
+	BidderIDs(tenderID int64) ([]int64, error)
+	// CountCreatedSince counts the bids submitted at or after since.
+	CountCreatedSince(since time.Time) (int64, error)
+	// Delete deletes the bid, provided its version is still bid.Version.
+	Delete(bid *model.Bid) error
+}
+
+type NotificationRepository interface {
+	Create(notification *model.Notification) error
+	GetByID(id int64) (*model.Notification, error)
+	// ListByUser returns the notifications of a user, newest first.
+	ListByUser(userID int64) ([]model.Notification, error)
+	// ListAfter returns the notifications of a user with an ID greater than lastID, oldest first.
+	ListAfter(userID, lastID int64) ([]model.Notification, error)
+	ListUndelivered(userID int64) ([]model.Notification, error)
+	// ClaimFallbackDue leases the oldest undelivered notification created
+	// before createdBefore that was not sent over offline channels yet, has
+	// failed fewer than maxAttempts times and whose next attempt is due at now.
+	// The lease moves its next attempt to leaseUntil, so no other worker sends
+	// it meanwhile. ClaimFallbackDue returns ErrNotFound when none is due.
+	ClaimFallbackDue(createdBefore, now, leaseUntil time.Time, maxAttempts int) (*model.Notification, error)
+	MarkDelivered(id int64, at time.Time) error
+	MarkFallbackSent(id int64, at time.Time) error
+	// RetryFallback records a failed offline send and when to try again.
+	RetryFallback(id int64, attempts int, at time.Time) error
+
+	GetPreferences(userID int64) ([]model.NotificationPreference, error)
+	GetPreference(userID int64, eventType string) (*model.NotificationPreference, error)
+	// SavePreference creates or replaces the preference for its event type.
+	SavePreference(preference *model.NotificationPreference) error
+	// GetSettings returns the settings of a user, empty when none were saved.
+	GetSettings(userID int64) (*model.NotificationSettings, error)
+	SaveSettings(settings *model.NotificationSettings) error
+}
+
+type WebhookRepository interface {
+	CreateEndpoint(endpoint *model.WebhookEndpoint) error
+	GetEndpoint(id int64) (*model.WebhookEndpoint, error)
+	// ListEndpoints returns the endpoints of a user, oldest first.
+	ListEndpoints(userID int64) ([]model.WebhookEndpoint, error)
+	// UpdateEndpointState saves whether the endpoint is active and its failures.
+	UpdateEndpointState(endpoint *model.WebhookEndpoint) error
+	// RecordFailure counts a failed delivery to the endpoint and disables it
+	// once maxFailures deliveries in a row failed. It returns the endpoint as
+	// updated, so concurrent workers never lose each other's failures.
+	RecordFailure(id int64, maxFailures int, now time.Time) (*model.WebhookEndpoint, error)
+	// ResetFailures clears the failures of an active endpoint after a
+	// successful delivery. An endpoint disabled meanwhile stays disabled.
+	ResetFailures(id int64) error
+	// DeleteEndpoint deletes the endpoint and its delivery log.
+	DeleteEndpoint(id int64) error
+
+	CreateDelivery(delivery *model.WebhookDelivery) error
+	GetDelivery(id int64) (*model.WebhookDelivery, error)
+	// ListDeliveries returns the delivery log of an endpoint, newest first.
+	ListDeliveries(endpointID int64) ([]model.WebhookDelivery, error)
+	// ClaimDue leases the oldest pending delivery of an active endpoint whose
+	// next attempt is due at now by moving its next attempt to leaseUntil, so
+	// no other worker picks it up meanwhile. If the worker dies before saving
+	// the attempt, the delivery is retried once the lease expires. ClaimDue
+	// returns ErrNotFound when no delivery is due.
+	ClaimDue(now, leaseUntil time.Time) (*model.WebhookDelivery, error)
+	UpdateDelivery(delivery *model.WebhookDelivery) error
+}
+
+type SavedSearchRepository interface {
+	Create(search *model.SavedSearch) error
+	GetByID(id int64) (*model.SavedSearch, error)
+	ListByContractor(contractorID int64) ([]model.SavedSearch, error)
+	// ListByBudget returns the searches whose budget range includes budget.
+	ListByBudget(budget float64) ([]model.SavedSearch, error)
+	// ListWithDigest returns the searches that send daily or weekly digests.
+	ListWithDigest() ([]model.SavedSearch, error)
+	// Delete deletes the search and its matches.
+	Delete(id int64) error
+	// AddMatch records that the tender matches the search and reports whether
+	// it was new.
+	AddMatch(searchID, tenderID int64) (bool, error)
+	// ListUndigested returns the matched tenders not sent in a digest yet, by ID.
+	ListUndigested(searchID int64) ([]model.Tender, error)
+	// ClaimDigest moves the last digest of the search to at, unless it changed
+	// since the search was read, e.g. because another worker sent the digest.
+	// It reports whether the digest is claimed.
+	ClaimDigest(search *model.SavedSearch, at time.Time) (bool, error)
+	// MarkDigested records that the tenders were sent in a digest of the search at the given time.
+	MarkDigested(searchID int64, tenderIDs []int64, at time.Time) error
+}
+
+type ReminderRepository interface {
+	// ListByTenders returns the reminders recorded for the tenders.
+	ListByTenders(tenderIDs []int64) ([]model.DeadlineReminder, error)
+	// Create records a reminder and reports whether it was new. There is one
+	// reminder per tender and offset.
+	Create(reminder *model.DeadlineReminder) (bool, error)
+}
+
+// EventRepository records domain events for delivery after the transaction
+// that caused them commits.
+type EventRepository interface {
+	// WriteOutbox stores a message the outbox relay publishes to topic.
+	WriteOutbox(aggregateType string, aggregateID int64, topic string, msg broker.Message) error
+	// QueueWebhooks creates a pending delivery of the event for every active
+	// webhook endpoint subscribed to eventType. Only endpoints of recipients
+	// receive it, or every endpoint when recipients is empty.
+	QueueWebhooks(eventID, eventType string, payload []byte, recipients []int64) error
+}
@@ -0,0 +1,104 @@
+package safe_http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for hosts that are not publicly routable.
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// ErrRedirect is returned instead of following a redirect, whose target
+// would otherwise be a way around the address check.
+var ErrRedirect = errors.New("redirects are not followed")
+
+// reserved are the ranges besides loopback, link-local, private and
+// unspecified addresses that never belong to a public receiver.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64, which maps to IPv4 addresses
+}
+
+// Allowed reports whether ip is a public unicast address.
+func Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reserved {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL rejects URLs that are not http or https, or whose host is
+// localhost or an IP literal that is not allowed. Host names are resolved
+// when connecting, by the client of NewClient.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme %q is not http or https", u.Scheme)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !Allowed(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// NewClient returns a client that only connects to allowed addresses and
+// does not follow redirects. The address is checked after DNS resolution,
+// right before connecting, so a host name cannot be changed to resolve to
+// an internal address once it was checked. Proxies from the environment are
+// not used, as the check would then only see the proxy.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrRedirect
+		},
+	}
+}
+
+// control runs for every address the dialer connects to, after resolution.
+func control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !Allowed(addrPort.Addr()) {
+		return fmt.Errorf("connect to %s: %w", addrPort.Addr(), ErrForbiddenAddress)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package safe_http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+
+	for _, tt := range tests {
+		if got := Allowed(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://example.com/hook", false},
+		{"http://93.184.216.34:8080/hook", false},
+		{"http://localhost/hook", true},
+		{"http://LOCALHOST./hook", true},
+		{"http://api.localhost/hook", true},
+		{"http://127.0.0.1/hook", true},
+		{"http://[::1]:9000/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://10.0.0.5/hook", true},
+		{"ftp://example.com/hook", true},
+	}
+
+	for _, tt := range tests {
+		if err := CheckURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("CheckURL(%s) = %v, want error %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// The loopback server stands in for any internal service
+	_, err := NewClient(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("got %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestClientDoesNotFollowRedirects(t *testing.T) {
+	client := NewClient(time.Second)
+	// Dial through the plain transport to reach the loopback test server, and
+	// check that the redirect policy alone stops at the first response
+	client.Transport = http.DefaultTransport
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("redirect was followed")
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrRedirect) {
+		t.Fatalf("got %v, want ErrRedirect", err)
+	}
+}
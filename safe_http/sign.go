@@ -0,0 +1,34 @@
+package safe_http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers that carry the signature of a payload sent to a user's URL.
+const (
+	SignatureHeader = "X-Tender-Signature"
+	TimestampHeader = "X-Tender-Timestamp"
+)
+
+// Sign computes the hex HMAC-SHA256 of "<timestamp>.<payload>". Binding the
+// timestamp into the signature stops replays with a fresh timestamp.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetSignature sets the timestamp and signature headers of a request that
+// sends payload.
+func SetSignature(req *http.Request, secret string, payload []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, payload))
+}
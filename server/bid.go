@@ -2,47 +2,50 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"tender-backend/cache"
 	"tender-backend/custom_errors"
 	"tender-backend/model"
 	request_model "tender-backend/model/request"
-	"time"
-
-	"github.com/redis/go-redis/v9"
-	"gorm.io/gorm"
+	"tender-backend/repository"
+	"tender-backend/validation"
 )
 
 type BidService struct {
-	db            *gorm.DB
+	store         repository.Store
 	tenderService *TenderService
-	redis         *redis.Client
+	bidByID       *cache.Store[model.Bid]
+	tenderBids    *cache.Store[[]model.Bid]
 }
 
-func NewBidService(db *gorm.DB, redisClient *redis.Client) *BidService {
+// NewBidService shares the cache and its TTL with tenderService, so that
+// tender and bid writes invalidate one another's entries.
+func NewBidService(store repository.Store, tenderService *TenderService) *BidService {
 	return &BidService{
-		db:            db,
-		tenderService: NewTenderService(db, redisClient),
-		redis:         redisClient,
+		store:         store,
+		tenderService: tenderService,
+		bidByID:       cache.NewStore[model.Bid](tenderService.cache, "bid", tenderService.cacheTTL),
+		tenderBids:    cache.NewStore[[]model.Bid](tenderService.cache, "tender_bids", tenderService.cacheTTL),
 	}
 }
 
-func (s *BidService) CreateBid(req *request_model.CreateBidReq, tenderID int64, contractorID int64) (*model.Bid, *custom_errors.AppError) {
-	if err := s.validateCreateBidRequest(req); err != nil {
+func (s *BidService) CreateBid(ctx context.Context, req *request_model.CreateBidReq, tenderID int64, contractorID int64) (*model.Bid, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
 		return nil, err
 	}
 
-	var tender model.Tender
-	if err := s.db.First(&tender, tenderID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, custom_errors.NewNotFoundError("Tender not found")
+	tender, err := s.store.WithContext(ctx).Tenders().GetByID(tenderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errTenderNotFound
 		}
 		return nil, custom_errors.NewAppError(err)
 	}
 
 	if tender.Status != "open" {
-		return nil, custom_errors.NewBadRequestError("Tender is not open for bids")
+		return nil, custom_errors.NewBadRequestError("Tender is not open for bids").WithCode(custom_errors.CodeTenderNotOpen)
 	}
 
 	newBid := model.Bid{
@@ -54,115 +57,111 @@ func (s *BidService) CreateBid(req *request_model.CreateBidReq, tenderID int64,
 		Status:       "pending",
 	}
 
-	if err := s.db.Create(&newBid).Error; err != nil {
+	// Save the bid, its event and the notification of the tender owner together
+	if err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		if err := tx.Bids().Create(&newBid); err != nil {
+			return err
+		}
+
+		if err := recordEvent(ctx, tx, bidAggregate, newBid.ID, model.EventBidReceived, []int64{tender.ClientID}, &newBid); err != nil {
+			return err
+		}
+
+		payload := tenderEventPayload(tender)
+		payload["bid_id"] = newBid.ID
+		payload["price"] = newBid.Price
+		payload["delivery_time"] = newBid.DeliveryTime
+		return s.tenderService.notifications.NotifyTx(ctx, tx, tender.ClientID, model.EventBidReceived, payload)
+	}); err != nil {
 		return nil, custom_errors.NewAppError(err)
 	}
 
-	// Clear relevant cache for this tender's bids
-	s.clearBidsCache(tenderID)
+	// Invalidate the cached bids of the tender
+	s.tenderService.invalidateCache(ctx, tenderBidsTag(tenderID))
 
 	return &newBid, nil
 }
 
-func (s *BidService) GetBidByID(bidID, tenderID int64) (*model.Bid, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("bid_%d_tender_%d", bidID, tenderID)
-
-	// Check Redis cache
-	cachedBid, err := s.redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		// Cache hit: return cached bid
-		var bid model.Bid
-		if err := json.Unmarshal([]byte(cachedBid), &bid); err == nil {
-			return &bid, nil
+func (s *BidService) GetBidByID(ctx context.Context, bidID, tenderID int64) (*model.Bid, *custom_errors.AppError) {
+	cacheKey := fmt.Sprintf("%d:%d", tenderID, bidID)
+	bid, err := s.bidByID.Get(ctx, cacheKey, []string{bidTag(bidID)}, func() (model.Bid, error) {
+		bid, err := s.store.WithContext(ctx).Bids().GetByID(bidID)
+		if err != nil {
+			return model.Bid{}, err
 		}
-	}
-
-	// Cache miss: query from DB
-	var bid model.Bid
-	if err := s.db.Where("id = ? AND tender_id = ?", bidID, tenderID).First(&bid).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("bid not found")
+		if bid.TenderID != tenderID {
+			return model.Bid{}, repository.ErrNotFound
 		}
-		return nil, fmt.Errorf("failed to retrieve bid: %s", err.Error())
+		return *bid, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errBidNotFound
+		}
+		return nil, custom_errors.NewAppError(err)
 	}
 
-	// Cache the result
-	bidJSON, _ := json.Marshal(bid)
-	_ = s.redis.Set(ctx, cacheKey, bidJSON, 10*time.Minute).Err()
-
 	return &bid, nil
 }
 
-func (s *BidService) GetAllBids(tenderID int64) ([]model.Bid, *custom_errors.AppError) {
-	_, err := s.tenderService.GetTenderById(tenderID)
+func (s *BidService) GetAllBids(ctx context.Context, tenderID int64) ([]model.Bid, *custom_errors.AppError) {
+	_, err := s.tenderService.GetTenderById(ctx, tenderID)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("bids_tender_%d", tenderID)
-
-	// Check Redis cache
-	cachedBids, err2 := s.redis.Get(ctx, cacheKey).Result()
-	if err2 == nil {
-		// Cache hit: return cached bids
-		var bids []model.Bid
-		if err := json.Unmarshal([]byte(cachedBids), &bids); err == nil {
-			return bids, nil
-		}
+	bids, err2 := s.tenderBids.Get(ctx, strconv.FormatInt(tenderID, 10), []string{tenderBidsTag(tenderID)}, func() ([]model.Bid, error) {
+		return s.store.WithContext(ctx).Bids().ListByTender(tenderID)
+	})
+	if err2 != nil {
+		return nil, custom_errors.NewAppError(err2)
 	}
 
-	var bids []model.Bid
-	if err := s.db.Where("tender_id = ?", tenderID).Find(&bids).Error; err != nil {
+	return bids, nil
+}
+
+func (s *BidService) GetContractorBids(ctx context.Context, contractorID int64) ([]model.Bid, *custom_errors.AppError) {
+	bids, err := s.store.WithContext(ctx).Bids().ListByContractor(contractorID)
+	if err != nil {
 		return nil, custom_errors.NewAppError(err)
 	}
 
 	return bids, nil
 }
 
-func (s *BidService) validateCreateBidRequest(req *request_model.CreateBidReq) *custom_errors.AppError {
-	err := custom_errors.NewBadRequestError("Invalid bid data")
-
-	if req.DeliveryTime <= 0 {
-		return err
+// DeleteBid deletes a bid of the contractor if its version is one of versions;
+// empty versions match any version.
+func (s *BidService) DeleteBid(ctx context.Context, bidID, contractorID int64, versions []int64) *custom_errors.AppError {
+	bid, err := s.store.WithContext(ctx).Bids().GetByID(bidID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errBidNotFound
+		}
+		return custom_errors.NewAppError(err)
 	}
 
-	if req.Price <= 0 {
-		return err
+	if bid.ContractorID != contractorID {
+		return errBidNotFound
 	}
 
-	return nil
-}
-
-func (s *BidService) GetContractorBids(contractorID int64) ([]model.Bid, error) {
-	var bids []model.Bid
-	if err := s.db.Where("contractor_id = ?", contractorID).Find(&bids).Error; err != nil {
-		return nil, fmt.Errorf("failed to retrieve bids: %s", err.Error())
+	if !matchesVersion(versions, bid.Version) {
+		return errBidModified
 	}
 
-	return bids, nil
-}
-
-func (s *BidService) DeleteBid(bidID, contractorID int64) *custom_errors.AppError {
-	var bid model.Bid
-	if err := s.db.Where("id = ? AND contractor_id = ?", bidID, contractorID).First(&bid).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return custom_errors.NewNotFoundError("Bid not found or access denied")
+	if err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		if err := tx.Bids().Delete(bid); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, bidAggregate, bid.ID, model.EventBidDeleted, []int64{contractorID}, bid)
+	}); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errBidModified
 		}
 		return custom_errors.NewAppError(err)
 	}
 
-	if err := s.db.Delete(&bid).Error; err != nil {
-		return custom_errors.NewAppError(err)
-	}
+	// Invalidate the cached bid and the bids of its tender
+	s.tenderService.invalidateCache(ctx, bidTag(bid.ID), tenderBidsTag(bid.TenderID))
 
 	return nil
 }
-
-// clearBidsCache clears cached bids for a specific tender.
-func (s *BidService) clearBidsCache(tenderID int64) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("bids_tender_%d", tenderID)
-	_ = s.redis.Del(ctx, cacheKey).Err()
-}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"testing"
+)
+
+func TestCreateBidRequiresOpenTender(t *testing.T) {
+	s := newTestServices(t)
+	tender := s.createTender(t, s.createUser(t, "client"), "closed")
+
+	_, err := s.bids.CreateBid(context.Background(), &request_model.CreateBidReq{Price: 100, DeliveryTime: 10}, tender.ID, s.createUser(t, "contractor"))
+	assertCode(t, err, custom_errors.CodeTenderNotOpen)
+}
+
+func TestDeleteBid(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	contractorID := s.createUser(t, "contractor")
+	tender := s.createTender(t, s.createUser(t, "client"), "open")
+	bid := s.createBid(t, tender.ID, contractorID)
+
+	// Only the contractor who submitted the bid can delete it
+	assertCode(t, s.bids.DeleteBid(ctx, bid.ID, s.createUser(t, "contractor"), nil), custom_errors.CodeBidNotFound)
+	assertCode(t, s.bids.DeleteBid(ctx, bid.ID, contractorID, []int64{bid.Version + 1}), custom_errors.CodeResourceModified)
+
+	if err := s.bids.DeleteBid(ctx, bid.ID, contractorID, []int64{bid.Version}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if n := s.outboxEvents(model.EventBidDeleted); n != 1 {
+		t.Errorf("%d bid_deleted events, want 1", n)
+	}
+
+	assertCode(t, s.bids.DeleteBid(ctx, bid.ID, contractorID, nil), custom_errors.CodeBidNotFound)
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"tender-backend/cache"
+	"tender-backend/circuit_breaker"
+)
+
+const cacheNamespace = "tender-backend"
+
+// Cache tags. Every write invalidates the tags of the data it changes after
+// its transaction commits.
+const tendersTag = "tenders"
+
+func tenderTag(tenderID int64) string {
+	return fmt.Sprintf("tender:%d", tenderID)
+}
+
+func tenderBidsTag(tenderID int64) string {
+	return fmt.Sprintf("tender_bids:%d", tenderID)
+}
+
+func bidTag(bidID int64) string {
+	return fmt.Sprintf("bid:%d", bidID)
+}
+
+// invalidateCache marks the cached entries with any of tags stale. Failures are
+// retried by the cache itself before it reads Redis again.
+func (t *TenderService) invalidateCache(ctx context.Context, tags ...string) {
+	if err := t.cache.Invalidate(ctx, tags...); err != nil && !errors.Is(err, circuit_breaker.ErrOpen) {
+		slog.WarnContext(ctx, "Failed to invalidate cache tags", "tags", tags, "error", err)
+	}
+}
+
+// CacheStats describes the tender and bid cache for health checks and metrics.
+func (t *TenderService) CacheStats() cache.Stats {
+	return t.cache.Stats()
+}
@@ -0,0 +1,11 @@
+package server
+
+import "tender-backend/custom_errors"
+
+// Errors shared by the services. Ownership failures are reported as not
+// found, so clients cannot probe for resources of other users.
+var (
+	errTenderNotFound = custom_errors.NewNotFoundError("Tender not found or access denied").WithCode(custom_errors.CodeTenderNotFound)
+	errBidNotFound    = custom_errors.NewNotFoundError("Bid not found or access denied").WithCode(custom_errors.CodeBidNotFound)
+	errUserNotFound   = custom_errors.NewNotFoundError("User not found").WithCode(custom_errors.CodeUserNotFound)
+)
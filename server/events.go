@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"tender-backend/broker"
+	"tender-backend/logging"
+	"tender-backend/repository"
+	"time"
+)
+
+// Aggregates domain events are recorded for. Events are published to the
+// "events.<aggregate>" topic.
+const (
+	tenderAggregate = "tender"
+	bidAggregate    = "bid"
+)
+
+// Event is the JSON envelope of domain events, published to the broker and
+// delivered to webhook endpoints.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// recordEvent writes a domain event to the outbox and queues it for the
+// subscribed webhook endpoints within tx, so both happen only if the change
+// they describe commits.
+func recordEvent(ctx context.Context, tx repository.Store, aggregateType string, aggregateID int64, eventType string, recipients []int64, data interface{}) error {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+
+	event := &Event{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Events().WriteOutbox(aggregateType, aggregateID, "events."+aggregateType, broker.Message{
+		Body:    payload,
+		Headers: logging.Inject(ctx, map[string]string{"event_type": eventType, "event_id": eventID}),
+	}); err != nil {
+		return err
+	}
+
+	return tx.Events().QueueWebhooks(eventID, eventType, payload, recipients)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"tender-backend/broker"
+	response_model "tender-backend/model/response"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// healthCheckTimeout bounds each dependency check, so a hanging dependency
+// fails the probe instead of timing it out.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthService checks the dependencies for the readiness probe.
+type HealthService struct {
+	db       *gorm.DB
+	redis    *redis.Client
+	broker   broker.Broker
+	draining atomic.Bool
+}
+
+func NewHealthService(db *gorm.DB, redisClient *redis.Client, b broker.Broker) *HealthService {
+	return &HealthService{
+		db:     db,
+		redis:  redisClient,
+		broker: b,
+	}
+}
+
+// Drain makes the service report not ready from now on, so load balancers
+// stop sending it requests while it shuts down.
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
+
+// Readiness checks PostgreSQL, Redis and the broker concurrently and reports
+// whether the service can take requests. Redis is not required, requests are
+// served from PostgreSQL while it is down.
+func (s *HealthService) Readiness(ctx context.Context) (*response_model.ReadinessRes, bool) {
+	checks := map[string]func(context.Context) error{
+		"postgres": func(ctx context.Context) error { return s.db.WithContext(ctx).Exec("SELECT 1").Error },
+		"redis":    func(ctx context.Context) error { return s.redis.Ping(ctx).Err() },
+		"broker":   s.broker.Ping,
+	}
+
+	res := &response_model.ReadinessRes{Status: "ok", Checks: make(map[string]response_model.DependencyRes, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			dependency := response_model.DependencyRes{Status: "ok"}
+			if err := check(checkCtx); err != nil {
+				dependency.Status = "unavailable"
+				dependency.Error = err.Error()
+			}
+			dependency.Duration = time.Since(start).String()
+
+			mu.Lock()
+			res.Checks[name] = dependency
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case s.draining.Load():
+		res.Status = "draining"
+	case res.Checks["postgres"].Status != "ok" || res.Checks["broker"].Status != "ok":
+		res.Status = "unavailable"
+	case res.Checks["redis"].Status != "ok":
+		res.Status = "degraded"
+	}
+	return res, res.Status == "ok" || res.Status == "degraded"
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"tender-backend/config"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/repository"
+	"testing"
+	"time"
+)
+
+// testServices wires the tender and bid services to an in-memory store,
+// without Redis or a broker.
+type testServices struct {
+	store   *repository.Memory
+	tenders *TenderService
+	bids    *BidService
+	users   int
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	store := repository.NewMemory()
+	notifications := NewNotificationService(store, nil, nil, "test", config.NotificationConfig{})
+	tenders := NewTenderService(store, nil, config.CacheConfig{TTL: time.Minute}, notifications, nil)
+
+	return &testServices{
+		store:   store,
+		tenders: tenders,
+		bids:    NewBidService(store, tenders),
+	}
+}
+
+func (s *testServices) createUser(t *testing.T, role string) int64 {
+	t.Helper()
+
+	s.users++
+	email := fmt.Sprintf("%s%d@example.com", role, s.users)
+	user := model.User{
+		FullName: role,
+		Password: "secret",
+		Role:     role,
+		Email:    email,
+		Username: email,
+	}
+	if err := s.store.Users().Create(&user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+func (s *testServices) createTender(t *testing.T, clientID int64, status string) *model.Tender {
+	t.Helper()
+
+	tender := model.Tender{
+		ClientID:    clientID,
+		Title:       "Office renovation",
+		Description: "Renovate two floors",
+		Deadline:    time.Now().Add(24 * time.Hour),
+		Budget:      10000,
+		Status:      status,
+	}
+	if err := s.store.Tenders().Create(&tender); err != nil {
+		t.Fatalf("create tender: %v", err)
+	}
+	return &tender
+}
+
+func (s *testServices) createBid(t *testing.T, tenderID, contractorID int64) *model.Bid {
+	t.Helper()
+
+	bid, err := s.bids.CreateBid(context.Background(), &request_model.CreateBidReq{Price: 9000, DeliveryTime: 30}, tenderID, contractorID)
+	if err != nil {
+		t.Fatalf("create bid: %v", err)
+	}
+	return bid
+}
+
+func (s *testServices) outboxEvents(eventType string) int {
+	count := 0
+	for _, msg := range s.store.Outbox() {
+		if msg.Message.Headers["event_type"] == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func assertCode(t *testing.T, err *custom_errors.AppError, code string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("got no error, want %s", code)
+	}
+	if err.Code != code {
+		t.Fatalf("got error %s (%v), want %s", err.Code, err, code)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"tender-backend/metrics"
+	"time"
+)
+
+// RunBusinessMetrics periodically refreshes the open tenders and bids per hour
+// gauges until ctx is done. Every replica reports the same values.
+func (t *TenderService) RunBusinessMetrics(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.refreshBusinessMetrics(context.WithoutCancel(ctx), time.Now()); err != nil {
+			slog.Error("Failed to refresh business metrics", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TenderService) refreshBusinessMetrics(ctx context.Context, now time.Time) error {
+	store := t.store.WithContext(ctx)
+
+	openTenders, err := store.Tenders().CountByStatus("open")
+	if err != nil {
+		return err
+	}
+
+	bids, err := store.Bids().CountCreatedSince(now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+
+	metrics.OpenTenders.Set(float64(openTenders))
+	metrics.BidsLastHour.Set(float64(bids))
+	return nil
+}
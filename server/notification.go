@@ -1,129 +1,643 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"google.golang.org/protobuf/proto"
-	"gorm.io/gorm"
-	"log"
+	"log/slog"
+	"strings"
+	"tender-backend/broker"
+	"tender-backend/config"
+	"tender-backend/custom_errors"
 	"tender-backend/gen_proto"
+	"tender-backend/logging"
+	"tender-backend/metrics"
 	"tender-backend/model"
 	request_model "tender-backend/model/request"
-	"tender-backend/rabbit_mq"
+	"tender-backend/notification_channel"
+	"tender-backend/notification_template"
+	"tender-backend/presence"
+	"tender-backend/repository"
+	"tender-backend/validation"
 	"tender-backend/web_socket"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultOfflineChannels is used for event types the user has no preference for.
+var defaultOfflineChannels = []string{model.ChannelEmail}
+
+// notificationsTopic is the shared topic every new notification is published to.
+const notificationsTopic = "notifications"
+
+const (
+	// Bounds of the wait before a notification that failed to route is retried.
+	routeMinBackoff = time.Second
+	routeMaxBackoff = 30 * time.Second
+
+	// fallbackMaxAttempts is how often the offline channels of a notification
+	// may all fail before it is only left for WebSocket delivery.
+	fallbackMaxAttempts = 5
+	// fallbackLease must outlast sending one notification over every channel.
+	// A notification in the user's quiet hours is checked again once it expires.
+	fallbackLease = 5 * time.Minute
 )
 
 type NotificationService struct {
-	db *gorm.DB
+	store         repository.Store
+	broker        broker.Broker
+	presence      *presence.Tracker
+	channels      map[string]notification_channel.Channel
+	templates     *notification_template.Registry
+	fallbackDelay time.Duration
 }
 
-func NewNotificationService(db *gorm.DB) *NotificationService {
+// NewNotificationService creates the service with the given offline channels,
+// usually notification_channel.DefaultChannels. instanceID identifies this
+// replica to the other ones routing notifications to its connections.
+func NewNotificationService(store repository.Store, redisClient *redis.Client, b broker.Broker, instanceID string, cfg config.NotificationConfig, channels ...notification_channel.Channel) *NotificationService {
+	channelsByName := make(map[string]notification_channel.Channel, len(channels))
+	for _, channel := range channels {
+		channelsByName[channel.Name()] = channel
+	}
+
 	return &NotificationService{
-		db: db,
+		store:         store,
+		broker:        b,
+		presence:      presence.NewTracker(redisClient, instanceID),
+		channels:      channelsByName,
+		templates:     notification_template.Default(),
+		fallbackDelay: cfg.FallbackDelay,
 	}
 }
 
-func (s *NotificationService) CreateNotification(notification *request_model.CreateNotificationReq) (*model.Notification, error) {
+// CreateNotification stores a notification and queues it for real-time
+// delivery through the outbox. When no message is given, it is rendered from
+// the event type template in the user's locale.
+func (s *NotificationService) CreateNotification(ctx context.Context, notification *request_model.CreateNotificationReq) (*model.Notification, error) {
+	if err := validation.Struct(notification); err != nil {
+		return nil, err
+	}
+
+	var newNotification *model.Notification
+	err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		var err error
+		newNotification, err = s.createNotification(ctx, tx, notification)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newNotification, nil
+}
+
+func (s *NotificationService) createNotification(ctx context.Context, tx repository.Store, notification *request_model.CreateNotificationReq) (*model.Notification, error) {
+	message := notification.Message
+	if message == "" {
+		user, err := tx.Users().GetByID(notification.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered, err := s.templates.Render(notification.EventType, user.Locale, notification.Payload)
+		if err != nil {
+			return nil, err
+		}
+		message = rendered
+	}
+
 	newNotification := model.Notification{
 		UserID:      notification.UserID,
-		Message:     notification.Message,
+		EventType:   notification.EventType,
+		Message:     message,
+		Payload:     string(notification.Payload),
 		IsDelivered: false,
 		DeliveredAt: nil,
 	}
 
-	if err := s.db.Create(&newNotification).Error; err != nil {
+	if err := tx.Notifications().Create(&newNotification); err != nil {
+		return nil, err
+	}
+
+	notificationBytes, err := proto.Marshal(toNotificationProto(&newNotification))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Events().WriteOutbox("notification", newNotification.ID, notificationsTopic, broker.Message{
+		Body:    notificationBytes,
+		Headers: logging.Inject(ctx, nil),
+	}); err != nil {
 		return nil, err
 	}
 
 	return &newNotification, nil
 }
 
-func (s *NotificationService) ConsumeNotifications() {
-	messages, err := rabbit_mq.Consume("notifications")
+// Notify creates a notification for the event from its template and queues it
+// for real-time delivery.
+func (s *NotificationService) Notify(ctx context.Context, userID int64, eventType string, payload interface{}) error {
+	return s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		return s.NotifyTx(ctx, tx, userID, eventType, payload)
+	})
+}
+
+// NotifyTx is Notify within tx, so the notification is only sent if the
+// change that caused it commits.
+func (s *NotificationService) NotifyTx(ctx context.Context, tx repository.Store, userID int64, eventType string, payload interface{}) error {
+	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		log.Fatalf("Failed to start consumer: %v", err)
+		return err
+	}
+
+	_, err = s.createNotification(ctx, tx, &request_model.CreateNotificationReq{
+		UserID:    userID,
+		EventType: eventType,
+		Payload:   payloadJSON,
+	})
+	return err
+}
+
+func (s *NotificationService) publish(ctx context.Context, notification *model.Notification) error {
+	notificationBytes, err := proto.Marshal(toNotificationProto(notification))
+	if err != nil {
+		return err
 	}
 
-	for msg := range messages {
-		var notification gen_proto.Notification
-		if err := proto.Unmarshal(msg.Body, &notification); err != nil {
-			log.Printf("Failed to unmarshal notification: %v", err)
+	return s.broker.Publish(ctx, notificationsTopic, broker.Message{
+		Body:    notificationBytes,
+		Headers: logging.Inject(ctx, nil),
+	})
+}
+
+// GetNotifications returns the notifications of a user, newest first. Messages
+// with a stored payload are re-rendered in the requested locale, or in the
+// user's locale when none is given.
+func (s *NotificationService) GetNotifications(ctx context.Context, userID int64, locale string) ([]model.Notification, *custom_errors.AppError) {
+	if locale == "" {
+		user, err := s.store.WithContext(ctx).Users().GetByID(userID)
+		if err != nil {
+			return nil, custom_errors.NewAppError(err)
+		}
+		locale = user.Locale
+	}
+
+	notifications, err := s.store.WithContext(ctx).Notifications().ListByUser(userID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	for i := range notifications {
+		if notifications[i].Payload == "" || !s.templates.Has(notifications[i].EventType) {
 			continue
 		}
 
-		// check if notification delivery is already handled
-		notificationFromDb, err := s.getNotificationByID(notification.Id)
+		message, err := s.templates.Render(notifications[i].EventType, locale, []byte(notifications[i].Payload))
 		if err != nil {
-			log.Printf("Failed to get notification from DB: %v", err)
+			slog.WarnContext(ctx, "Failed to render notification", "notification_id", notifications[i].ID, "error", err)
 			continue
 		}
+		notifications[i].Message = message
+	}
+
+	return notifications, nil
+}
+
+// ConsumeNotifications consumes the shared notifications topic and routes each
+// notification to the topic of every instance the user is connected to, until
+// ctx is done. It returns an error if it cannot subscribe.
+func (s *NotificationService) ConsumeNotifications(ctx context.Context) error {
+	deliveries, err := s.broker.Subscribe(ctx, notificationsTopic, broker.SubscribeOptions{})
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", notificationsTopic, err)
+	}
 
-		if notificationFromDb.IsDelivered {
+	// Routing fails while Redis or the broker is down, so the consumer backs
+	// off before giving the notification back instead of retrying it at once.
+	backoff := routeMinBackoff
+	for delivery := range deliveries {
+		if err := s.routeNotification(delivery); err != nil {
+			slog.ErrorContext(logging.Extract(delivery.Context(), delivery.Headers), "Failed to route notification", "error", err, "retry_in", backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+			}
+			backoff = min(2*backoff, routeMaxBackoff)
+			_ = delivery.Nack(true)
 			continue
 		}
+		backoff = routeMinBackoff
+		_ = delivery.Ack()
+	}
 
-		err = s.db.Transaction(func(tx *gorm.DB) error {
-			// Mark notification as delivered
-			err := s.markNotificationAsDelivered(notification.Id)
+	return nil
+}
 
-			if err != nil {
-				return err
-			}
+func (s *NotificationService) routeNotification(delivery *broker.Delivery) error {
+	ctx := logging.Extract(delivery.Context(), delivery.Headers)
 
-			// Check WebSocket connection
-			return web_socket.SendNotification(notification.UserId, msg.Body)
-		})
+	var notification gen_proto.Notification
+	if err := proto.Unmarshal(delivery.Body, &notification); err != nil {
+		// A malformed message will never succeed, so it is dropped.
+		slog.ErrorContext(ctx, "Failed to unmarshal notification", "error", err)
+		return nil
+	}
 
-		if err != nil {
-			log.Printf("Failed to handle notification: %v", err)
+	instances, err := s.presence.Instances(ctx, notification.UserId)
+	if err != nil {
+		return err
+	}
+
+	// Offline users keep the notification pending for the offline fallback.
+	slog.DebugContext(ctx, "Routing notification", "notification_id", notification.Id, "user_id", notification.UserId, "instances", instances)
+	for _, instanceID := range instances {
+		if err := s.broker.Publish(ctx, instanceTopic(instanceID), delivery.Message); err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
-func (s *NotificationService) getNotificationByID(id int64) (*model.Notification, error) {
-	var notification model.Notification
-	if err := s.db.First(&notification, id).Error; err != nil {
-		return nil, err
+// ConsumeInstanceNotifications delivers notifications routed to this instance
+// to the locally connected WebSocket and SSE clients, until ctx is done. It
+// returns an error if it cannot subscribe.
+func (s *NotificationService) ConsumeInstanceNotifications(ctx context.Context) error {
+	topic := instanceTopic(s.presence.InstanceID())
+	deliveries, err := s.broker.Subscribe(ctx, topic, broker.SubscribeOptions{Ephemeral: true})
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", topic, err)
+	}
+
+	for delivery := range deliveries {
+		s.deliverLocally(logging.Extract(delivery.Context(), delivery.Headers), delivery.Body)
+		_ = delivery.Ack()
+	}
+
+	return nil
+}
+
+func (s *NotificationService) deliverLocally(ctx context.Context, body []byte) {
+	var notification gen_proto.Notification
+	if err := proto.Unmarshal(body, &notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to unmarshal notification", "error", err)
+		return
+	}
+
+	// check if notification delivery is already handled
+	notificationFromDb, err := s.getNotificationByID(ctx, notification.Id)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get notification from DB", "notification_id", notification.Id, "error", err)
+		return
+	}
+
+	if notificationFromDb.IsDelivered {
+		return
+	}
+
+	// Only mark the notification as delivered once the WebSocket accepted it,
+	// otherwise it stays pending for the offline fallback.
+	if err := web_socket.SendNotification(&notification); err != nil {
+		metrics.NotificationsFailed.WithLabelValues("websocket").Inc()
+		slog.WarnContext(ctx, "Failed to send notification over WebSocket", "notification_id", notification.Id, "error", err)
+		return
+	}
+
+	metrics.NotificationsDelivered.WithLabelValues("websocket").Inc()
+
+	if err := s.markNotificationAsDelivered(ctx, notification.Id); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark notification as delivered", "notification_id", notification.Id, "error", err)
+		return
 	}
+	slog.InfoContext(ctx, "Delivered notification", "notification_id", notification.Id, "user_id", notification.UserId)
+}
+
+// UserConnected records the first local connection of a user in the presence registry.
+func (s *NotificationService) UserConnected(userID int64) error {
+	return s.presence.Join(context.Background(), userID)
+}
 
-	return &notification, nil
+// UserDisconnected removes this instance from the user's presence after the last local connection closed.
+func (s *NotificationService) UserDisconnected(userID int64) error {
+	return s.presence.Leave(context.Background(), userID)
 }
 
-func (s *NotificationService) markNotificationAsDelivered(id int64) error {
-	notification, err := s.getNotificationByID(id)
+// RunPresenceHeartbeat keeps the presence of locally connected users alive until ctx is done.
+func (s *NotificationService) RunPresenceHeartbeat(ctx context.Context) {
+	s.presence.Run(ctx, web_socket.OnlineUsers)
+}
+
+func instanceTopic(instanceID string) string {
+	return "notifications.instance." + instanceID
+}
+
+func (s *NotificationService) getNotificationByID(ctx context.Context, id int64) (*model.Notification, error) {
+	return s.store.WithContext(ctx).Notifications().GetByID(id)
+}
+
+func (s *NotificationService) markNotificationAsDelivered(ctx context.Context, id int64) error {
+	return s.store.WithContext(ctx).Notifications().MarkDelivered(id, time.Now())
+}
+
+func (s *NotificationService) PublishNotDeliveredNotificationsForUser(ctx context.Context, userID int64) error {
+	notifications, err := s.store.WithContext(ctx).Notifications().ListUndelivered(userID)
 	if err != nil {
 		return err
 	}
 
-	notification.IsDelivered = true
-	if err := s.db.Save(notification).Error; err != nil {
+	for i := range notifications {
+		if err := s.publish(ctx, &notifications[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplayNotificationsAfter pushes every notification of the user with an ID
+// greater than lastID to conn, oldest first, and marks them as delivered.
+// It lets a reconnecting SSE client resume from its Last-Event-ID.
+func (s *NotificationService) ReplayNotificationsAfter(ctx context.Context, userID, lastID int64, conn web_socket.Connection) error {
+	notifications, err := s.store.WithContext(ctx).Notifications().ListAfter(userID, lastID)
+	if err != nil {
 		return err
 	}
 
+	for i := range notifications {
+		if err := conn.Send(toNotificationProto(&notifications[i])); err != nil {
+			return err
+		}
+
+		if notifications[i].IsDelivered {
+			continue
+		}
+
+		if err := s.markNotificationAsDelivered(ctx, notifications[i].ID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s *NotificationService) PublishNotDeliveredNotificationsForUser(userID int64) error {
-	var notifications []model.Notification
+// GetPreferences returns the per-event-type channel preferences of a user.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int64) ([]model.NotificationPreference, *custom_errors.AppError) {
+	preferences, err := s.store.WithContext(ctx).Notifications().GetPreferences(userID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return preferences, nil
+}
 
-	if err := s.db.Where("user_id = ? AND is_delivered = ?", userID, false).Find(&notifications).Error; err != nil {
+// SetPreference creates or replaces the channel preference for one event type.
+func (s *NotificationService) SetPreference(ctx context.Context, userID int64, req *request_model.SetNotificationPreferenceReq) (*model.NotificationPreference, *custom_errors.AppError) {
+	if err := s.validatePreference(req); err != nil {
+		return nil, err
+	}
+
+	preference := model.NotificationPreference{
+		UserID:    userID,
+		EventType: req.EventType,
+		Channels:  strings.Join(req.Channels, ","),
+	}
+
+	if err := s.store.WithContext(ctx).Notifications().SavePreference(&preference); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return &preference, nil
+}
+
+func (s *NotificationService) validatePreference(req *request_model.SetNotificationPreferenceReq) *custom_errors.AppError {
+	if err := validation.Struct(req); err != nil {
 		return err
 	}
 
-	for _, notification := range notifications {
-		notificationProto := &gen_proto.Notification{
-			Id:      notification.ID,
-			UserId:  notification.UserID,
-			Message: notification.Message,
+	// Channels can be valid but not set up on this server
+	var fields []custom_errors.FieldError
+	for i, channel := range req.Channels {
+		if _, ok := s.channels[channel]; !ok {
+			fields = append(fields, custom_errors.FieldError{
+				Field:   fmt.Sprintf("channels[%d]", i),
+				Message: "is not enabled on this server",
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return custom_errors.NewValidationError(fields...)
+	}
+
+	return nil
+}
+
+// GetSettings returns the contact details and quiet hours of a user.
+func (s *NotificationService) GetSettings(ctx context.Context, userID int64) (*model.NotificationSettings, *custom_errors.AppError) {
+	settings, err := s.store.WithContext(ctx).Notifications().GetSettings(userID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings replaces the contact details and quiet hours of a user.
+func (s *NotificationService) UpdateSettings(ctx context.Context, userID int64, req *request_model.UpdateNotificationSettingsReq) (*model.NotificationSettings, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.WithContext(ctx).Notifications().GetSettings(userID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	// A new URL gets a new secret, so a previous receiver cannot forge
+	// payloads for the new one.
+	secret := existing.WebhookSecret
+	if req.WebhookURL == "" {
+		secret = ""
+	} else if req.WebhookURL != existing.WebhookURL || secret == "" {
+		if secret, err = randomHex(32); err != nil {
+			return nil, custom_errors.NewAppError(err)
 		}
+	}
+
+	settings := model.NotificationSettings{
+		UserID:          userID,
+		Phone:           req.Phone,
+		WebhookURL:      req.WebhookURL,
+		WebhookSecret:   secret,
+		QuietHoursStart: req.QuietHoursStart,
+		QuietHoursEnd:   req.QuietHoursEnd,
+		TimeZone:        req.TimeZone,
+	}
+
+	if err := s.store.WithContext(ctx).Notifications().SaveSettings(&settings); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return &settings, nil
+}
+
+// RunOfflineFallback periodically sends notifications that stayed undelivered
+// on WebSocket longer than the fallback delay over the user's offline channels.
+// It stops once ctx is done.
+func (s *NotificationService) RunOfflineFallback(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// A round that started is finished, so no claimed notification waits for its lease.
+			if err := s.dispatchOfflineFallbacks(context.WithoutCancel(ctx), now); err != nil {
+				slog.Error("Failed to dispatch offline notifications", "error", err)
+			}
+		}
+	}
+}
 
-		notificationBytes, err := proto.Marshal(notificationProto)
+// dispatchOfflineFallbacks claims and sends due notifications one at a time,
+// so replicas share the work and never send the same notification twice.
+func (s *NotificationService) dispatchOfflineFallbacks(ctx context.Context, now time.Time) error {
+	for {
+		notification, err := s.store.WithContext(ctx).Notifications().ClaimFallbackDue(now.Add(-s.fallbackDelay), now, time.Now().Add(fallbackLease), fallbackMaxAttempts)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
 
-		if err := rabbit_mq.Publish("notifications", notificationBytes); err != nil {
-			return err
+		if err := s.sendOffline(ctx, notification, now); err != nil {
+			slog.ErrorContext(ctx, "Failed to send notification over offline channels", "notification_id", notification.ID, "error", err)
+			s.retryOffline(ctx, notification, now)
 		}
 	}
+}
 
-	return nil
+// retryOffline records a failed offline send and backs off before the next
+// one: the fallback delay, doubled after each failure.
+func (s *NotificationService) retryOffline(ctx context.Context, notification *model.Notification, now time.Time) {
+	attempts := notification.FallbackAttempts + 1
+	if attempts >= fallbackMaxAttempts {
+		slog.WarnContext(ctx, "Giving up sending notification over offline channels", "notification_id", notification.ID, "attempts", attempts)
+	}
+
+	retryAt := now.Add(s.fallbackDelay * time.Duration(1<<(attempts-1)))
+	if err := s.store.WithContext(ctx).Notifications().RetryFallback(notification.ID, attempts, retryAt); err != nil {
+		slog.ErrorContext(ctx, "Failed to record offline send attempt", "notification_id", notification.ID, "error", err)
+	}
+}
+
+// sendOffline sends a notification over the offline channels of its user. It
+// is marked as sent once a channel accepted it or when no channel applies, e.g.
+// the user has no address for any of them. If every channel that applies
+// fails, it returns an error and the notification is retried.
+func (s *NotificationService) sendOffline(ctx context.Context, notification *model.Notification, now time.Time) error {
+	user, err := s.store.WithContext(ctx).Users().GetByID(notification.UserID)
+	if err != nil {
+		return err
+	}
+
+	settings, appErr := s.GetSettings(ctx, notification.UserID)
+	if appErr != nil {
+		return appErr
+	}
+
+	// Keep the notification pending until the quiet hours are over.
+	if inQuietHours(settings, now) {
+		return nil
+	}
+
+	channelNames, err := s.offlineChannelsFor(ctx, notification.UserID, notification.EventType)
+	if err != nil {
+		return err
+	}
+
+	recipient := &notification_channel.Recipient{User: user, Settings: settings}
+	sent, failed := 0, 0
+	for _, name := range channelNames {
+		channel, ok := s.channels[name]
+		if !ok {
+			continue
+		}
+
+		err := channel.Send(recipient, notification)
+		switch {
+		case err == nil:
+			sent++
+			metrics.NotificationsDelivered.WithLabelValues(name).Inc()
+		case !errors.Is(err, notification_channel.ErrNoAddress):
+			failed++
+			metrics.NotificationsFailed.WithLabelValues(name).Inc()
+			slog.WarnContext(ctx, "Failed to send notification", "notification_id", notification.ID, "channel", name, "error", err)
+		}
+	}
+
+	if sent == 0 && failed > 0 {
+		return fmt.Errorf("all %d offline channels failed", failed)
+	}
+
+	return s.store.WithContext(ctx).Notifications().MarkFallbackSent(notification.ID, now)
+}
+
+func (s *NotificationService) offlineChannelsFor(ctx context.Context, userID int64, eventType string) ([]string, error) {
+	preference, err := s.store.WithContext(ctx).Notifications().GetPreference(userID, eventType)
+	if errors.Is(err, repository.ErrNotFound) {
+		return defaultOfflineChannels, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if preference.Channels == "" {
+		return nil, nil
+	}
+
+	return strings.Split(preference.Channels, ","), nil
+}
+
+// inQuietHours reports whether now falls into the user's quiet hours, in
+// the user's time zone. Ranges that cross midnight, such as 22:00-07:00, are
+// supported.
+func inQuietHours(settings *model.NotificationSettings, now time.Time) bool {
+	start, err := parseClock(settings.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(settings.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	location, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		// Only valid zones are stored, but one may vanish from the zone database.
+		slog.Warn("Unknown time zone, using UTC for quiet hours", "user_id", settings.UserID, "time_zone", settings.TimeZone)
+		location = time.UTC
+	}
+	now = now.In(location)
+
+	current := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return current >= start && current < end
+	}
+	return current >= start || current < end
+}
+
+// parseClock converts "HH:MM" into minutes since midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
 }
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"tender-backend/gen_proto"
+	"tender-backend/model"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NotificationSchemaVersion is the version of gen_proto.Notification sent to
+// clients. Messages without a version use the original id/user_id/message schema.
+const NotificationSchemaVersion = 2
+
+var notificationEventTypes = map[string]gen_proto.EventType{
+	model.EventTenderCreated:       gen_proto.EventType_EVENT_TYPE_TENDER_CREATED,
+	model.EventTenderStatusChanged: gen_proto.EventType_EVENT_TYPE_TENDER_STATUS_CHANGED,
+	model.EventTenderAwarded:       gen_proto.EventType_EVENT_TYPE_TENDER_AWARDED,
+	model.EventBidReceived:         gen_proto.EventType_EVENT_TYPE_BID_RECEIVED,
+	model.EventTenderMatched:       gen_proto.EventType_EVENT_TYPE_TENDER_MATCHED,
+	model.EventSavedSearchDigest:   gen_proto.EventType_EVENT_TYPE_SAVED_SEARCH_DIGEST,
+	model.EventTenderDeleted:       gen_proto.EventType_EVENT_TYPE_TENDER_DELETED,
+	model.EventBidDeleted:          gen_proto.EventType_EVENT_TYPE_BID_DELETED,
+	model.EventDeadlineReminder:    gen_proto.EventType_EVENT_TYPE_DEADLINE_REMINDER,
+}
+
+var notificationPriorities = map[string]gen_proto.Priority{
+	model.EventTenderAwarded:     gen_proto.Priority_PRIORITY_HIGH,
+	model.EventTenderDeleted:     gen_proto.Priority_PRIORITY_HIGH,
+	model.EventDeadlineReminder:  gen_proto.Priority_PRIORITY_HIGH,
+	model.EventBidReceived:       gen_proto.Priority_PRIORITY_NORMAL,
+	model.EventSavedSearchDigest: gen_proto.Priority_PRIORITY_LOW,
+}
+
+// notificationPayload holds the payload fields written by tenderEventPayload
+// and the bid and award notifications.
+type notificationPayload struct {
+	TenderID     int64     `json:"tender_id"`
+	Title        string    `json:"title"`
+	Budget       float64   `json:"budget"`
+	Deadline     time.Time `json:"deadline"`
+	Status       string    `json:"status"`
+	BidID        int64     `json:"bid_id"`
+	Price        float64   `json:"price"`
+	DeliveryTime int32     `json:"delivery_time"`
+}
+
+func toNotificationProto(notification *model.Notification) *gen_proto.Notification {
+	message := &gen_proto.Notification{
+		Id:            notification.ID,
+		UserId:        notification.UserID,
+		Message:       notification.Message,
+		SchemaVersion: NotificationSchemaVersion,
+		EventType:     notificationEventTypes[notification.EventType],
+		Priority:      gen_proto.Priority_PRIORITY_NORMAL,
+		CreatedAt:     timestamppb.New(notification.CreatedAt),
+	}
+
+	if priority, ok := notificationPriorities[notification.EventType]; ok {
+		message.Priority = priority
+	}
+
+	if notification.Payload == "" {
+		return message
+	}
+
+	var payload notificationPayload
+	if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+		slog.Warn("Failed to decode notification payload", "notification_id", notification.ID, "error", err)
+		return message
+	}
+
+	if payload.TenderID == 0 {
+		// Digests cover several tenders, so they carry no typed payload.
+		return message
+	}
+
+	tender := &gen_proto.TenderEvent{
+		TenderId: payload.TenderID,
+		Title:    payload.Title,
+		Budget:   payload.Budget,
+		Status:   payload.Status,
+	}
+	if !payload.Deadline.IsZero() {
+		tender.Deadline = timestamppb.New(payload.Deadline)
+	}
+	message.Resource = &gen_proto.ResourceRef{
+		Type: "tender",
+		Id:   payload.TenderID,
+		Path: fmt.Sprintf("/api/client/tenders/%d", payload.TenderID),
+	}
+
+	switch notification.EventType {
+	case model.EventBidReceived, model.EventBidDeleted:
+		message.Payload = &gen_proto.Notification_Bid{Bid: &gen_proto.BidEvent{
+			Tender:       tender,
+			BidId:        payload.BidID,
+			Price:        payload.Price,
+			DeliveryTime: payload.DeliveryTime,
+		}}
+		message.Resource = &gen_proto.ResourceRef{
+			Type: "bid",
+			Id:   payload.BidID,
+			Path: fmt.Sprintf("/api/contractor/tenders/%d/bid/%d", payload.TenderID, payload.BidID),
+		}
+	case model.EventTenderAwarded:
+		message.Payload = &gen_proto.Notification_Award{Award: &gen_proto.AwardEvent{
+			Tender: tender,
+			BidId:  payload.BidID,
+		}}
+	default:
+		message.Payload = &gen_proto.Notification_Tender{Tender: tender}
+	}
+
+	return message
+}
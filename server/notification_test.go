@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"tender-backend/config"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/notification_channel"
+	"testing"
+	"time"
+)
+
+// fakeChannel records the notifications sent over it and fails with err.
+type fakeChannel struct {
+	err   error
+	sends int
+}
+
+func (c *fakeChannel) Name() string { return model.ChannelEmail }
+
+func (c *fakeChannel) Send(*notification_channel.Recipient, *model.Notification) error {
+	c.sends++
+	return c.err
+}
+
+func TestOfflineFallbackRetries(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	channel := &fakeChannel{err: errors.New("smtp unavailable")}
+	delay := time.Minute
+	notifications := NewNotificationService(s.store, nil, nil, "test", config.NotificationConfig{FallbackDelay: delay}, channel)
+
+	now := time.Now()
+	notification := model.Notification{UserID: s.createUser(t, "contractor"), EventType: model.EventTenderAwarded, Message: "Awarded", CreatedAt: now.Add(-delay)}
+	if err := s.store.Notifications().Create(&notification); err != nil {
+		t.Fatalf("create notification: %v", err)
+	}
+
+	// A failed send is retried after a backoff, up to the attempt limit
+	for round := 0; round < fallbackMaxAttempts+2; round++ {
+		if err := notifications.dispatchOfflineFallbacks(ctx, now); err != nil {
+			t.Fatalf("dispatch: %v", err)
+		}
+		now = now.Add(time.Duration(1<<fallbackMaxAttempts) * delay)
+	}
+	if channel.sends != fallbackMaxAttempts {
+		t.Errorf("%d sends, want %d", channel.sends, fallbackMaxAttempts)
+	}
+
+	stored, err := s.store.Notifications().GetByID(notification.ID)
+	if err != nil {
+		t.Fatalf("get notification: %v", err)
+	}
+	if stored.FallbackSentAt != nil {
+		t.Errorf("notification marked as sent after every send failed")
+	}
+}
+
+func TestOfflineFallbackSent(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	channel := &fakeChannel{}
+	notifications := NewNotificationService(s.store, nil, nil, "test", config.NotificationConfig{FallbackDelay: time.Minute}, channel)
+
+	now := time.Now()
+	notification := model.Notification{UserID: s.createUser(t, "contractor"), EventType: model.EventTenderAwarded, Message: "Awarded", CreatedAt: now.Add(-time.Hour)}
+	if err := s.store.Notifications().Create(&notification); err != nil {
+		t.Fatalf("create notification: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := notifications.dispatchOfflineFallbacks(ctx, now.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("dispatch: %v", err)
+		}
+	}
+	if channel.sends != 1 {
+		t.Errorf("%d sends, want 1", channel.sends)
+	}
+}
+
+func TestWebhookSecretRotation(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	notifications := NewNotificationService(s.store, nil, nil, "test", config.NotificationConfig{})
+	userID := s.createUser(t, "contractor")
+
+	update := func(url string) string {
+		t.Helper()
+		settings, err := notifications.UpdateSettings(ctx, userID, &request_model.UpdateNotificationSettingsReq{WebhookURL: url})
+		if err != nil {
+			t.Fatalf("update settings: %v", err)
+		}
+		return settings.WebhookSecret
+	}
+
+	first := update("https://example.com/hook")
+	if first == "" {
+		t.Fatal("no secret generated for a webhook URL")
+	}
+	if same := update("https://example.com/hook"); same != first {
+		t.Errorf("secret changed without a URL change")
+	}
+	if rotated := update("https://example.org/hook"); rotated == first || rotated == "" {
+		t.Errorf("secret %q not rotated for a new URL", rotated)
+	}
+	if cleared := update(""); cleared != "" {
+		t.Errorf("secret %q kept without a URL", cleared)
+	}
+
+	_, err := notifications.UpdateSettings(ctx, userID, &request_model.UpdateNotificationSettingsReq{WebhookURL: "http://169.254.169.254/latest"})
+	assertCode(t, err, custom_errors.CodeValidationFailed)
+}
+
+func TestInQuietHours(t *testing.T) {
+	// 23:30 UTC is 08:30 the next morning in Tokyo and 19:30 in New York
+	now := time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		start, end, timeZone string
+		want                 bool
+	}{
+		{"22:00", "07:00", "", true},
+		{"22:00", "07:00", "UTC", true},
+		{"22:00", "07:00", "Asia/Tokyo", false},
+		{"08:00", "09:00", "Asia/Tokyo", true},
+		{"19:00", "20:00", "America/New_York", true},
+		{"22:00", "07:00", "America/New_York", false},
+		{"", "", "Asia/Tokyo", false},
+	}
+
+	for _, tt := range tests {
+		settings := &model.NotificationSettings{QuietHoursStart: tt.start, QuietHoursEnd: tt.end, TimeZone: tt.timeZone}
+		if got := inQuietHours(settings, now); got != tt.want {
+			t.Errorf("%s-%s in %q: got %v, want %v", tt.start, tt.end, tt.timeZone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"tender-backend/custom_errors"
+	response_model "tender-backend/model/response"
+	"tender-backend/rate_limiter"
+	"time"
+)
+
+// RateLimitService lets administrators inspect and reset rate limit keys.
+type RateLimitService struct {
+	limiter  *rate_limiter.Limiter
+	policies rate_limiter.Policies
+}
+
+func NewRateLimitService(limiter *rate_limiter.Limiter, policies rate_limiter.Policies) *RateLimitService {
+	return &RateLimitService{
+		limiter:  limiter,
+		policies: policies,
+	}
+}
+
+func (s *RateLimitService) GetPolicies() rate_limiter.Policies {
+	return s.policies
+}
+
+// GetUsage returns the usage of every active key, optionally of one policy.
+// Keys look like "<policy>:<user|ip|api_key|organization>:<value>".
+func (s *RateLimitService) GetUsage(policyName string) ([]response_model.RateLimitUsageRes, *custom_errors.AppError) {
+	prefix := ""
+	if policyName != "" {
+		if _, ok := s.policies.Find(policyName); !ok {
+			return nil, custom_errors.NewNotFoundError("Rate limit policy not found")
+		}
+		prefix = policyName + ":"
+	}
+
+	ctx := context.Background()
+	keys, err := s.limiter.Keys(ctx, prefix)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	usage := make([]response_model.RateLimitUsageRes, 0, len(keys))
+	for _, key := range keys {
+		name, _, _ := strings.Cut(key, ":")
+		policy, ok := s.policies.Find(name)
+		if !ok {
+			// Left over from a policy that was removed from the configuration.
+			continue
+		}
+
+		result, err := s.limiter.Usage(ctx, key, policy.Limit, time.Duration(policy.Window))
+		if err != nil {
+			return nil, custom_errors.NewAppError(err)
+		}
+
+		usage = append(usage, response_model.RateLimitUsageRes{
+			Key:       key,
+			Policy:    policy.Name,
+			Limit:     policy.Limit,
+			Used:      policy.Limit - result.Remaining,
+			Remaining: result.Remaining,
+			Reset:     result.Reset,
+		})
+	}
+
+	return usage, nil
+}
+
+// ResetKey clears the window of a key, e.g. "bid-submission:user:42".
+func (s *RateLimitService) ResetKey(key string) *custom_errors.AppError {
+	if key == "" {
+		return custom_errors.NewBadRequestError("Key is required")
+	}
+
+	deleted, err := s.limiter.Reset(context.Background(), key)
+	if err != nil {
+		return custom_errors.NewAppError(err)
+	}
+
+	if !deleted {
+		return custom_errors.NewNotFoundError("Rate limit key not found")
+	}
+
+	return nil
+}
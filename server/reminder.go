@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"tender-backend/model"
+	"tender-backend/repository"
+	"time"
+)
+
+type ReminderService struct {
+	store         repository.Store
+	notifications *NotificationService
+	offsets       []time.Duration // ascending
+}
+
+// NewReminderService creates a scheduler that reminds the participants of open
+// tenders at each offset before the deadline.
+func NewReminderService(store repository.Store, notifications *NotificationService, offsets []time.Duration) *ReminderService {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &ReminderService{
+		store:         store,
+		notifications: notifications,
+		offsets:       sorted,
+	}
+}
+
+// RunReminders periodically sends the deadline reminders that are due, until ctx is done.
+func (s *ReminderService) RunReminders(ctx context.Context, interval time.Duration) {
+	if len(s.offsets) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.sendDueReminders(ctx, now); err != nil {
+				slog.ErrorContext(ctx, "Failed to send deadline reminders", "error", err)
+			}
+		}
+	}
+}
+
+func (s *ReminderService) sendDueReminders(ctx context.Context, now time.Time) error {
+	maxOffset := s.offsets[len(s.offsets)-1]
+
+	tenders, err := s.store.WithContext(ctx).Tenders().ListOpenClosing(now, now.Add(maxOffset))
+	if err != nil {
+		return err
+	}
+
+	if len(tenders) == 0 {
+		return nil
+	}
+
+	tenderIDs := make([]int64, 0, len(tenders))
+	for i := range tenders {
+		tenderIDs = append(tenderIDs, tenders[i].ID)
+	}
+
+	reminders, err := s.store.WithContext(ctx).Reminders().ListByTenders(tenderIDs)
+	if err != nil {
+		return err
+	}
+
+	sent := make(map[int64]map[int]bool)
+	for _, reminder := range reminders {
+		if sent[reminder.TenderID] == nil {
+			sent[reminder.TenderID] = make(map[int]bool)
+		}
+		sent[reminder.TenderID][reminder.OffsetMinutes] = true
+	}
+
+	for i := range tenders {
+		due := s.dueOffsets(&tenders[i], now)
+		if len(due) == 0 || sent[tenders[i].ID][offsetMinutes(due[0])] {
+			continue
+		}
+
+		if err := s.sendReminder(ctx, &tenders[i], due, now); err != nil {
+			slog.ErrorContext(ctx, "Failed to send deadline reminder", "tender_id", tenders[i].ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// dueOffsets returns the offsets whose reminder time has passed, closest to the deadline first.
+func (s *ReminderService) dueOffsets(tender *model.Tender, now time.Time) []time.Duration {
+	remaining := tender.Deadline.Sub(now)
+
+	var due []time.Duration
+	for _, offset := range s.offsets {
+		if remaining <= offset {
+			due = append(due, offset)
+		}
+	}
+	return due
+}
+
+// sendReminder sends the reminder for the closest due offset. The reminder row
+// is written in the same transaction as the notifications and is unique per
+// tender and offset, so only one replica sends it and a restart never repeats
+// it. Farther offsets that were missed, e.g. while the service was down or
+// because the tender was created close to its deadline, are recorded as
+// skipped instead of sending a burst of stale reminders.
+func (s *ReminderService) sendReminder(ctx context.Context, tender *model.Tender, due []time.Duration, now time.Time) error {
+	return s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		for i, offset := range due {
+			created, err := tx.Reminders().Create(&model.DeadlineReminder{
+				TenderID:      tender.ID,
+				OffsetMinutes: offsetMinutes(offset),
+				Skipped:       i > 0,
+			})
+			if err != nil {
+				return err
+			}
+
+			if i == 0 && !created {
+				// Another replica sent this reminder in the meantime.
+				return nil
+			}
+		}
+
+		recipients, err := reminderRecipients(tx, tender)
+		if err != nil {
+			return err
+		}
+
+		payload := tenderEventPayload(tender)
+		payload["hours_left"] = int(math.Ceil(tender.Deadline.Sub(now).Hours()))
+		for _, userID := range recipients {
+			if err := s.notifications.NotifyTx(ctx, tx, userID, model.EventDeadlineReminder, payload); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// reminderRecipients returns the owner of a tender and every contractor who
+// bid on or watches it, without duplicates.
+func reminderRecipients(tx repository.Store, tender *model.Tender) ([]int64, error) {
+	bidderIDs, err := tx.Bids().BidderIDs(tender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	watcherIDs, err := tx.Tenders().WatcherIDs(tender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := []int64{tender.ClientID}
+	seen := map[int64]bool{tender.ClientID: true}
+	for _, contractorID := range append(bidderIDs, watcherIDs...) {
+		if !seen[contractorID] {
+			seen[contractorID] = true
+			recipients = append(recipients, contractorID)
+		}
+	}
+	return recipients, nil
+}
+
+func offsetMinutes(offset time.Duration) int {
+	return int(offset / time.Minute)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"tender-backend/model"
+	"testing"
+	"time"
+)
+
+func TestSendDueReminders(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	clientID := s.createUser(t, "client")
+	contractorID := s.createUser(t, "contractor")
+	tender := s.createTender(t, clientID, "open")
+	s.createBid(t, tender.ID, contractorID)
+
+	reminders := NewReminderService(s.store, s.tenders.notifications, []time.Duration{time.Hour, 48 * time.Hour})
+
+	// The tender closes in 24 hours, so only the 48 hour reminder is due
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := reminders.sendDueReminders(ctx, now); err != nil {
+			t.Fatalf("send reminders: %v", err)
+		}
+	}
+
+	for _, userID := range []int64{clientID, contractorID} {
+		list, err := s.store.Notifications().ListByUser(userID)
+		if err != nil {
+			t.Fatalf("list notifications: %v", err)
+		}
+		count := 0
+		for _, n := range list {
+			if n.EventType == model.EventDeadlineReminder {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Errorf("user %d got %d deadline reminders, want 1", userID, count)
+		}
+	}
+}
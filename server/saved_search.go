@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/repository"
+	"tender-backend/validation"
+	"time"
+
+	"gorm.io/gorm/utils"
+)
+
+var digestPeriods = map[string]time.Duration{
+	model.DigestDaily:  24 * time.Hour,
+	model.DigestWeekly: 7 * 24 * time.Hour,
+}
+
+type SavedSearchService struct {
+	store         repository.Store
+	notifications *NotificationService
+}
+
+func NewSavedSearchService(store repository.Store, notifications *NotificationService) *SavedSearchService {
+	return &SavedSearchService{
+		store:         store,
+		notifications: notifications,
+	}
+}
+
+// CreateSavedSearch stores the search criteria of a contractor.
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, req *request_model.CreateSavedSearchReq, contractorID int64) (*model.SavedSearch, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	instantAlerts := true
+	if req.InstantAlerts != nil {
+		instantAlerts = *req.InstantAlerts
+	}
+
+	digest := req.Digest
+	if digest == "" {
+		digest = model.DigestNone
+	}
+
+	search := model.SavedSearch{
+		ContractorID:  contractorID,
+		Name:          req.Name,
+		Keywords:      joinTerms(req.Keywords),
+		Categories:    joinTerms(req.Categories),
+		MinBudget:     req.MinBudget,
+		MaxBudget:     req.MaxBudget,
+		InstantAlerts: instantAlerts,
+		Digest:        digest,
+	}
+
+	if err := s.store.WithContext(ctx).SavedSearches().Create(&search); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return &search, nil
+}
+
+func (s *SavedSearchService) GetSavedSearches(ctx context.Context, contractorID int64) ([]model.SavedSearch, *custom_errors.AppError) {
+	searches, err := s.store.WithContext(ctx).SavedSearches().ListByContractor(contractorID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return searches, nil
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, searchID, contractorID int64) *custom_errors.AppError {
+	search, err := s.store.WithContext(ctx).SavedSearches().GetByID(searchID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return custom_errors.NewAppError(err)
+	}
+	if err != nil || search.ContractorID != contractorID {
+		return custom_errors.NewNotFoundError("Saved search not found or access denied")
+	}
+
+	if err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		return tx.SavedSearches().Delete(search.ID)
+	}); err != nil {
+		return custom_errors.NewAppError(err)
+	}
+
+	return nil
+}
+
+// MatchTender records the tender against every saved search it matches and
+// notifies the contractors that asked for instant alerts. ctx carries the ID
+// of the request that created the tender into the notifications.
+func (s *SavedSearchService) MatchTender(ctx context.Context, tender model.Tender) {
+	searches, err := s.store.WithContext(ctx).SavedSearches().ListByBudget(tender.Budget)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load saved searches", "tender_id", tender.ID, "error", err)
+		return
+	}
+
+	for _, search := range searches {
+		if !searchMatches(&search, &tender) {
+			continue
+		}
+
+		created, err := s.store.WithContext(ctx).SavedSearches().AddMatch(search.ID, tender.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to record saved search match", "search_id", search.ID, "error", err)
+			continue
+		}
+
+		if !created || !search.InstantAlerts {
+			continue
+		}
+
+		payload := tenderEventPayload(&tender)
+		payload["search_id"] = search.ID
+		payload["search_name"] = search.Name
+		if err := s.notifications.Notify(ctx, search.ContractorID, model.EventTenderMatched, payload); err != nil {
+			slog.ErrorContext(ctx, "Failed to notify contractor", "contractor_id", search.ContractorID, "error", err)
+		}
+	}
+}
+
+// searchMatches checks the keyword and category criteria; budget is filtered by the repository.
+func searchMatches(search *model.SavedSearch, tender *model.Tender) bool {
+	if search.Categories != "" && !utils.Contains(strings.Split(search.Categories, ","), strings.ToLower(tender.Category)) {
+		return false
+	}
+
+	if search.Keywords == "" {
+		return true
+	}
+
+	text := strings.ToLower(tender.Title + " " + tender.Description)
+	for _, keyword := range strings.Split(search.Keywords, ",") {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RunDigests periodically sends one message per saved search that groups all
+// matches since its last daily or weekly digest, until ctx is done.
+func (s *SavedSearchService) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Shutdown waits for the digests of this round instead of cutting them off.
+			if err := s.sendDueDigests(context.WithoutCancel(ctx), now); err != nil {
+				slog.ErrorContext(ctx, "Failed to send saved search digests", "error", err)
+			}
+		}
+	}
+}
+
+func (s *SavedSearchService) sendDueDigests(ctx context.Context, now time.Time) error {
+	searches, err := s.store.WithContext(ctx).SavedSearches().ListWithDigest()
+	if err != nil {
+		return err
+	}
+
+	for i := range searches {
+		last := searches[i].CreatedAt
+		if searches[i].LastDigestAt != nil {
+			last = *searches[i].LastDigestAt
+		}
+
+		if now.Sub(last) < digestPeriods[searches[i].Digest] {
+			continue
+		}
+
+		if err := s.sendDigest(ctx, &searches[i], now); err != nil {
+			slog.ErrorContext(ctx, "Failed to send saved search digest", "search_id", searches[i].ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// sendDigest claims the digest of the search and sends it in one transaction,
+// so replicas running the same round send it once, and the notification is
+// only created together with the matches it marks as digested.
+func (s *SavedSearchService) sendDigest(ctx context.Context, search *model.SavedSearch, now time.Time) error {
+	return s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		// The claim locks the search until the transaction ends.
+		claimed, err := tx.SavedSearches().ClaimDigest(search, now)
+		if err != nil || !claimed {
+			return err
+		}
+
+		tenders, err := tx.SavedSearches().ListUndigested(search.ID)
+		if err != nil || len(tenders) == 0 {
+			return err
+		}
+
+		items := make([]map[string]interface{}, 0, len(tenders))
+		tenderIDs := make([]int64, 0, len(tenders))
+		for i := range tenders {
+			items = append(items, tenderEventPayload(&tenders[i]))
+			tenderIDs = append(tenderIDs, tenders[i].ID)
+		}
+
+		if err := s.notifications.NotifyTx(ctx, tx, search.ContractorID, model.EventSavedSearchDigest, map[string]interface{}{
+			"search_id":   search.ID,
+			"search_name": search.Name,
+			"tenders":     items,
+		}); err != nil {
+			return err
+		}
+
+		return tx.SavedSearches().MarkDigested(search.ID, tenderIDs, now)
+	})
+}
+
+// joinTerms lowercases and trims search terms and joins them with commas.
+func joinTerms(terms []string) string {
+	cleaned := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term != "" && !strings.Contains(term, ",") {
+			cleaned = append(cleaned, term)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
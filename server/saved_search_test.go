@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"tender-backend/model"
+	"testing"
+	"time"
+)
+
+func TestSendDueDigests(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	contractorID := s.createUser(t, "contractor")
+	tender := s.createTender(t, s.createUser(t, "client"), "open")
+
+	now := time.Now()
+	search := model.SavedSearch{ContractorID: contractorID, Name: "Renovations", Digest: model.DigestDaily, CreatedAt: now.Add(-48 * time.Hour)}
+	if err := s.store.SavedSearches().Create(&search); err != nil {
+		t.Fatalf("create search: %v", err)
+	}
+	if _, err := s.store.SavedSearches().AddMatch(search.ID, tender.ID); err != nil {
+		t.Fatalf("add match: %v", err)
+	}
+
+	searches := NewSavedSearchService(s.store, s.tenders.notifications)
+	for i := 0; i < 2; i++ {
+		if err := searches.sendDueDigests(ctx, now); err != nil {
+			t.Fatalf("send digests: %v", err)
+		}
+	}
+
+	notifications, err := s.store.Notifications().ListByUser(contractorID)
+	if err != nil {
+		t.Fatalf("list notifications: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].EventType != model.EventSavedSearchDigest {
+		t.Errorf("notifications = %+v, want one saved_search_digest", notifications)
+	}
+
+	// A search read before another worker sent the digest cannot claim it again
+	if claimed, err := s.store.SavedSearches().ClaimDigest(&search, now); err != nil || claimed {
+		t.Errorf("claim of a stale search = %v, %v, want false", claimed, err)
+	}
+}
@@ -2,33 +2,51 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"log/slog"
+	"strconv"
+	"tender-backend/cache"
+	"tender-backend/config"
 	"tender-backend/custom_errors"
 	"tender-backend/model"
 	request_model "tender-backend/model/request"
+	"tender-backend/repository"
+	"tender-backend/validation"
 	"time"
 
 	"github.com/redis/go-redis/v9"
-	"gorm.io/gorm"
 )
 
 type TenderService struct {
-	db    *gorm.DB
-	redis *redis.Client
+	store         repository.Store
+	cache         *cache.Cache
+	cacheTTL      time.Duration
+	tenders       *cache.Store[[]model.Tender]
+	tenderByID    *cache.Store[model.Tender]
+	notifications *NotificationService
+	savedSearches *SavedSearchService
 }
 
-// NewTenderService initializes a new TenderService with the database connection.
-func NewTenderService(db *gorm.DB, redisClient *redis.Client) *TenderService {
+// NewTenderService initializes a new TenderService with the store. redisClient
+// and savedSearches may be nil, which disables caching and saved search alerts.
+func NewTenderService(store repository.Store, redisClient *redis.Client, cacheConfig config.CacheConfig, notifications *NotificationService, savedSearches *SavedSearchService) *TenderService {
+	local := cache.NewLocal(cacheConfig.LocalSize, cacheConfig.LocalTTL)
+	tenderCache := cache.New(redisClient, cacheNamespace, local)
+
 	return &TenderService{
-		db:    db,
-		redis: redisClient,
+		store:         store,
+		cache:         tenderCache,
+		cacheTTL:      cacheConfig.TTL,
+		tenders:       cache.NewStore[[]model.Tender](tenderCache, "tenders", cacheConfig.TTL),
+		tenderByID:    cache.NewStore[model.Tender](tenderCache, "tender", cacheConfig.TTL),
+		notifications: notifications,
+		savedSearches: savedSearches,
 	}
 }
 
 // CreateTender creates a new tender in the database.
-func (t *TenderService) CreateTender(req *request_model.CreateTenderReq, clientID int64) (*model.Tender, *custom_errors.AppError) {
-	if err := validateCreateTender(req); err != nil {
+func (t *TenderService) CreateTender(ctx context.Context, req *request_model.CreateTenderReq, clientID int64) (*model.Tender, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
 		return nil, err
 	}
 
@@ -38,44 +56,43 @@ func (t *TenderService) CreateTender(req *request_model.CreateTenderReq, clientI
 		Description: req.Description,
 		Deadline:    req.Deadline,
 		Budget:      req.Budget,
+		Category:    req.Category,
 		Status:      "open",
 	}
 
-	// Save the tender to the database.
-	if err := t.db.Create(tender).Error; err != nil {
+	// Save the tender together with its event to the database.
+	if err := t.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		if err := tx.Tenders().Create(tender); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, tenderAggregate, tender.ID, model.EventTenderCreated, nil, tender)
+	}); err != nil {
 		return nil, custom_errors.NewAppError(err)
 	}
 
 	// Invalidate the cache after creating a new tender
-	t.redis.Del(context.Background(), "tenders_cache")
-
-	return tender, nil
-}
+	t.invalidateCache(ctx, tendersTag)
 
-// ValidateCreateTender validates the input for creating a tender.
-func validateCreateTender(req *request_model.CreateTenderReq) *custom_errors.AppError {
-	if req.Title == "" {
-		return custom_errors.NewBadRequestError("Invalid input")
-	}
-	if req.Deadline.Before(time.Now()) {
-		return custom_errors.NewBadRequestError("Invalid tender data")
-	}
-
-	if req.Budget <= 0 {
-		return custom_errors.NewBadRequestError("Invalid tender data")
+	// Alert contractors whose saved searches match the new tender
+	if t.savedSearches != nil {
+		go t.savedSearches.MatchTender(context.WithoutCancel(ctx), *tender)
 	}
 
-	return nil
+	return tender, nil
 }
 
 // GetTenderById retrieves a tender by its ID.
-func (t *TenderService) GetTenderById(id int64) (*model.Tender, *custom_errors.AppError) {
-	var tender model.Tender
-
-	// Try fetching the tender from the database
-	if err := t.db.First(&tender, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, custom_errors.NewNotFoundError("Tender not found or access denied")
+func (t *TenderService) GetTenderById(ctx context.Context, id int64) (*model.Tender, *custom_errors.AppError) {
+	tender, err := t.tenderByID.Get(ctx, strconv.FormatInt(id, 10), []string{tenderTag(id)}, func() (model.Tender, error) {
+		tender, err := t.store.WithContext(ctx).Tenders().GetByID(id)
+		if err != nil {
+			return model.Tender{}, err
+		}
+		return *tender, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errTenderNotFound
 		}
 		return nil, custom_errors.NewAppError(err)
 	}
@@ -84,163 +101,221 @@ func (t *TenderService) GetTenderById(id int64) (*model.Tender, *custom_errors.A
 }
 
 // GetTenders retrieves all tenders from the cache or database.
-func (t *TenderService) GetTenders() ([]model.Tender, error) {
-	// Redis context
-	ctx := context.Background()
-
-	// Redis key for caching tenders
-	cacheKey := "tenders_cache"
-
-	// Try fetching the tenders from Redis
-	cachedTenders, err := t.redis.Get(ctx, cacheKey).Result()
-	if err == nil && cachedTenders != "" {
-		// If cached data exists, unmarshal it and return
-		var tenders []model.Tender
-		if err := json.Unmarshal([]byte(cachedTenders), &tenders); err == nil {
-			return tenders, nil
-		}
-	}
-
-	// If cache miss or unmarshal error, fetch from the database
-	var tenders []model.Tender
-	if err := t.db.Find(&tenders).Error; err != nil {
-		return nil, err
-	}
-
-	// Marshal the tenders to JSON and store in Redis
-	tendersJSON, err := json.Marshal(tenders)
-	if err == nil {
-		// Cache the tenders for 10 minutes
-		_ = t.redis.Set(ctx, cacheKey, tendersJSON, 10*time.Minute).Err()
+func (t *TenderService) GetTenders(ctx context.Context) ([]model.Tender, *custom_errors.AppError) {
+	tenders, err := t.tenders.Get(ctx, "all", []string{tendersTag}, func() ([]model.Tender, error) {
+		return t.store.WithContext(ctx).Tenders().List()
+	})
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
 	}
 
 	return tenders, nil
 }
 
-// UpdateTender updates the tender with the given ID.
-func (t *TenderService) UpdateTender(tenderID, clientID int64, req *request_model.UpdateTenderReq) (*model.Tender, *custom_errors.AppError) {
+// UpdateTender updates the tender with the given ID if its version is one of
+// versions, the versions named by If-Match; empty versions match any version.
+func (t *TenderService) UpdateTender(ctx context.Context, tenderID, clientID int64, req *request_model.UpdateTenderReq, versions []int64) (*model.Tender, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
 	// Validate that the tender belongs to the client
-	if err := t.ValidateTenderBelongsToUser(tenderID, clientID); err != nil {
+	if err := t.ValidateTenderBelongsToUser(ctx, tenderID, clientID); err != nil {
 		return nil, err
 	}
 
-	var tender model.Tender
-	if err := t.db.First(&tender, tenderID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, custom_errors.NewNotFoundError("Tender not found or access denied")
+	tender, err := t.store.WithContext(ctx).Tenders().GetByID(tenderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errTenderNotFound
 		}
 		return nil, custom_errors.NewAppError(err)
 	}
 
+	if !matchesVersion(versions, tender.Version) {
+		return nil, errTenderModified
+	}
+
 	// Validate the update request
-	if err := ValidateTenderUpdate(tender.Status, req.Status); err != nil {
+	if err := ValidateTenderUpdate(tender.Status); err != nil {
 		return nil, err
 	}
 
-	tender.Status = req.Status
+	// Save the updated tender, its event and the bidder notifications to the database
+	if err := t.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		// The version check is repeated in the update, so a concurrent edit fails instead of being overwritten.
+		if err := tx.Tenders().UpdateStatus(tender, req.Status); err != nil {
+			return err
+		}
 
-	// Save the updated tender to the database
-	if err := t.db.Save(&tender).Error; err != nil {
+		if err := recordEvent(ctx, tx, tenderAggregate, tender.ID, model.EventTenderStatusChanged, t.tenderParticipants(ctx, tx, tender), tender); err != nil {
+			return err
+		}
+		return t.notifyBidders(ctx, tx, tender, model.EventTenderStatusChanged, tenderEventPayload(tender))
+	}); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, errTenderModified
+		}
 		return nil, custom_errors.NewAppError(err)
 	}
 
 	// Invalidate the cache after updating the tender
-	t.redis.Del(context.Background(), "tenders_cache")
+	t.invalidateCache(ctx, tendersTag, tenderTag(tenderID))
 
-	return &tender, nil
+	return tender, nil
 }
 
-func ValidateTenderUpdate(existingStatus, newStatus string) *custom_errors.AppError {
-	// Reject updates if the existing status is not "open"
+// ValidateTenderUpdate only lets open tenders change. The requested status is
+// checked by the tender_status rule, which leaves awarding to AwardTender.
+func ValidateTenderUpdate(existingStatus string) *custom_errors.AppError {
 	if existingStatus != "open" {
-		return custom_errors.NewAppError(errors.New("updates are only allowed for tenders with 'open' status"))
-	}
-
-	// Reject updates if the new status is "awarded"
-	if newStatus == "awarded" {
-		return custom_errors.NewAppError(errors.New("status cannot be updated to 'awarded'"))
+		return custom_errors.NewBadRequestError("Updates are only allowed for tenders with 'open' status").WithCode(custom_errors.CodeTenderNotOpen)
 	}
 
 	return nil
 }
 
-// DeleteTender deletes a tender by its ID.
-func (t *TenderService) DeleteTender(tenderID, clientID int64) *custom_errors.AppError {
+// DeleteTender deletes a tender by its ID if its version is one of versions;
+// empty versions match any version.
+func (t *TenderService) DeleteTender(ctx context.Context, tenderID, clientID int64, versions []int64) *custom_errors.AppError {
 	// Validate that the tender belongs to the client
-	if err := t.ValidateTenderBelongsToUser(tenderID, clientID); err != nil {
+	if err := t.ValidateTenderBelongsToUser(ctx, tenderID, clientID); err != nil {
 		return err
 	}
 
 	// Perform the deletion
-	if err := t.db.Delete(&model.Tender{}, tenderID).Error; err != nil {
+	if err := t.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		if err := tx.Tenders().Delete(tenderID, versions); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, tenderAggregate, tenderID, model.EventTenderDeleted, []int64{clientID}, map[string]int64{"tender_id": tenderID})
+	}); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errTenderModified
+		}
 		return custom_errors.NewAppError(err)
 	}
 
 	// Invalidate the cache after deleting the tender
-	t.redis.Del(context.Background(), "tenders_cache")
+	t.invalidateCache(ctx, tendersTag, tenderTag(tenderID), tenderBidsTag(tenderID))
 
 	return nil
 }
 
 // ValidateTenderBelongsToUser ensures that a tender belongs to a specific client.
-func (t *TenderService) ValidateTenderBelongsToUser(tenderID, clientID int64) *custom_errors.AppError {
-	notFoundError := custom_errors.NewNotFoundError("Tender not found or access denied")
-
-	var tender model.Tender
-
+func (t *TenderService) ValidateTenderBelongsToUser(ctx context.Context, tenderID, clientID int64) *custom_errors.AppError {
 	// Fetch the tender from the database
-	if err := t.db.First(&tender, tenderID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return notFoundError
+	tender, err := t.store.WithContext(ctx).Tenders().GetByID(tenderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errTenderNotFound
 		}
 		return custom_errors.NewAppError(err)
 	}
 
 	// Check if the tender belongs to the client
 	if tender.ClientID != clientID {
-		return notFoundError
+		return errTenderNotFound
 	}
 
 	return nil
 }
 
-func (t *TenderService) AwardTender(tenderID, clientID, bidID int64) *custom_errors.AppError {
+// AwardTender awards an open tender of the client to one of its bids.
+func (t *TenderService) AwardTender(ctx context.Context, tenderID, clientID, bidID int64) *custom_errors.AppError {
 	// Validate that the tender belongs to the user.
-	if err := t.ValidateTenderBelongsToUser(tenderID, clientID); err != nil {
-		return err
+	tender, err := t.store.WithContext(ctx).Tenders().GetByID(tenderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errTenderNotFound
+		}
+		return custom_errors.NewAppError(err)
+	}
+
+	if tender.ClientID != clientID {
+		return errTenderNotFound
 	}
 
 	// Validate that the bid belongs to the tender.
-	if err := t.ValidateBidBelongsToTender(bidID, tenderID); err != nil {
-		return err
+	bid, err := t.store.WithContext(ctx).Bids().GetByID(bidID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errBidNotFound
+		}
+		return custom_errors.NewAppError(err)
+	}
+
+	if bid.TenderID != tenderID {
+		return errBidNotFound
+	}
+
+	// Awarding a closed or awarded tender would repeat the award event and notifications
+	if tender.Status != "open" {
+		return custom_errors.NewBadRequestError("Only open tenders can be awarded").WithCode(custom_errors.CodeTenderNotOpen)
 	}
 
 	// Update the tender status to "awarded", and set the awarded contractor ID.
-	if err := t.db.Model(&model.Tender{}).Where("id = ?", tenderID).Updates(map[string]interface{}{
-		"status":                "awarded",
-		"awarded_contractor_id": bidID,
-	}).Error; err != nil {
+	if err := t.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		// The award is conditional on the version read above, so of two concurrent awards only one succeeds.
+		if err := tx.Tenders().Award(tender, bid.ContractorID); err != nil {
+			return err
+		}
+
+		if err := recordEvent(ctx, tx, tenderAggregate, tender.ID, model.EventTenderAwarded, t.tenderParticipants(ctx, tx, tender), &TenderAwardedEvent{
+			Tender: *tender,
+			BidID:  bidID,
+		}); err != nil {
+			return err
+		}
+		payload := tenderEventPayload(tender)
+		payload["bid_id"] = bidID
+		return t.notifyBidders(ctx, tx, tender, model.EventTenderAwarded, payload)
+	}); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errTenderModified
+		}
 		return custom_errors.NewAppError(err)
 	}
 
+	// Invalidate the cache after awarding the tender
+	t.invalidateCache(ctx, tendersTag, tenderTag(tenderID))
+
 	return nil
 }
 
-func (t *TenderService) ValidateBidBelongsToTender(bidID, tenderID int64) *custom_errors.AppError {
-	notFoundError := custom_errors.NewNotFoundError("Bid not found or access denied")
-
-	var bid model.Bid
+// notifyBidders creates a notification about the tender, within tx, for every contractor who bid on it.
+func (t *TenderService) notifyBidders(ctx context.Context, tx repository.Store, tender *model.Tender, eventType string, payload map[string]interface{}) error {
+	contractorIDs, err := tx.Bids().BidderIDs(tender.ID)
+	if err != nil {
+		return err
+	}
 
-	if err := t.db.First(&bid, bidID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return notFoundError
+	for _, contractorID := range contractorIDs {
+		if err := t.notifications.NotifyTx(ctx, tx, contractorID, eventType, payload); err != nil {
+			return err
 		}
-		return custom_errors.NewAppError(err)
 	}
 
-	if bid.TenderID != tenderID {
-		return notFoundError
+	return nil
+}
+
+// tenderEventPayload is the structured data notification templates render tender events from.
+func tenderEventPayload(tender *model.Tender) map[string]interface{} {
+	return map[string]interface{}{
+		"tender_id": tender.ID,
+		"title":     tender.Title,
+		"budget":    tender.Budget,
+		"deadline":  tender.Deadline,
+		"status":    tender.Status,
 	}
+}
 
-	return nil
+// tenderParticipants returns the owner of a tender and every contractor who bid on it.
+func (t *TenderService) tenderParticipants(ctx context.Context, tx repository.Store, tender *model.Tender) []int64 {
+	contractorIDs, err := tx.Bids().BidderIDs(tender.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load bidders", "tender_id", tender.ID, "error", err)
+	}
+
+	return append(contractorIDs, tender.ClientID)
 }
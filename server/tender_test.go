@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/repository"
+	"testing"
+)
+
+func TestAwardTender(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	clientID := s.createUser(t, "client")
+	contractorID := s.createUser(t, "contractor")
+	tender := s.createTender(t, clientID, "open")
+	bid := s.createBid(t, tender.ID, contractorID)
+
+	if err := s.tenders.AwardTender(ctx, tender.ID, clientID, bid.ID); err != nil {
+		t.Fatalf("award: %v", err)
+	}
+
+	awarded, err := s.store.Tenders().GetByID(tender.ID)
+	if err != nil {
+		t.Fatalf("get tender: %v", err)
+	}
+	if awarded.Status != "awarded" {
+		t.Errorf("status = %q, want awarded", awarded.Status)
+	}
+	if awarded.AwardedContractorID != contractorID {
+		t.Errorf("awarded contractor = %d, want contractor %d (bid %d)", awarded.AwardedContractorID, contractorID, bid.ID)
+	}
+	if awarded.Version != tender.Version+1 {
+		t.Errorf("version = %d, want %d", awarded.Version, tender.Version+1)
+	}
+	if n := s.outboxEvents(model.EventTenderAwarded); n != 1 {
+		t.Fatalf("%d tender_awarded events, want 1", n)
+	}
+
+	// A second award is rejected and records no event
+	assertCode(t, s.tenders.AwardTender(ctx, tender.ID, clientID, bid.ID), custom_errors.CodeTenderNotOpen)
+	if n := s.outboxEvents(model.EventTenderAwarded); n != 1 {
+		t.Errorf("%d tender_awarded events after a repeated award, want 1", n)
+	}
+}
+
+func TestAwardTenderRejects(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	clientID := s.createUser(t, "client")
+	otherClientID := s.createUser(t, "client")
+	contractorID := s.createUser(t, "contractor")
+
+	tender := s.createTender(t, clientID, "open")
+	bid := s.createBid(t, tender.ID, contractorID)
+	otherTender := s.createTender(t, clientID, "open")
+	otherBid := s.createBid(t, otherTender.ID, contractorID)
+
+	assertCode(t, s.tenders.AwardTender(ctx, tender.ID, otherClientID, bid.ID), custom_errors.CodeTenderNotFound)
+	assertCode(t, s.tenders.AwardTender(ctx, tender.ID, clientID, otherBid.ID), custom_errors.CodeBidNotFound)
+	assertCode(t, s.tenders.AwardTender(ctx, 999, clientID, bid.ID), custom_errors.CodeTenderNotFound)
+
+	if _, err := s.tenders.UpdateTender(ctx, tender.ID, clientID, &request_model.UpdateTenderReq{Status: "closed"}, nil); err != nil {
+		t.Fatalf("close tender: %v", err)
+	}
+	assertCode(t, s.tenders.AwardTender(ctx, tender.ID, clientID, bid.ID), custom_errors.CodeTenderNotOpen)
+
+	if n := s.outboxEvents(model.EventTenderAwarded); n != 0 {
+		t.Errorf("%d tender_awarded events, want 0", n)
+	}
+}
+
+func TestAwardConflict(t *testing.T) {
+	s := newTestServices(t)
+	tender := s.createTender(t, s.createUser(t, "client"), "open")
+
+	// A tender read before a concurrent change is not awarded
+	stale := *tender
+	if err := s.store.Tenders().UpdateStatus(tender, "closed"); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	if err := s.store.Tenders().Award(&stale, 1); err != repository.ErrConflict {
+		t.Fatalf("award of a stale tender: got %v, want ErrConflict", err)
+	}
+}
+
+func TestUpdateTender(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	clientID := s.createUser(t, "client")
+	contractorID := s.createUser(t, "contractor")
+	tender := s.createTender(t, clientID, "open")
+	s.createBid(t, tender.ID, contractorID)
+
+	// Awarding goes through AwardTender, never through a status update
+	_, err := s.tenders.UpdateTender(ctx, tender.ID, clientID, &request_model.UpdateTenderReq{Status: "awarded"}, nil)
+	assertCode(t, err, custom_errors.CodeValidationFailed)
+
+	// Another client cannot see the tender
+	_, err = s.tenders.UpdateTender(ctx, tender.ID, s.createUser(t, "client"), &request_model.UpdateTenderReq{Status: "closed"}, nil)
+	assertCode(t, err, custom_errors.CodeTenderNotFound)
+
+	// A stale If-Match version is rejected
+	_, err = s.tenders.UpdateTender(ctx, tender.ID, clientID, &request_model.UpdateTenderReq{Status: "closed"}, []int64{tender.Version + 1})
+	assertCode(t, err, custom_errors.CodeResourceModified)
+
+	updated, err := s.tenders.UpdateTender(ctx, tender.ID, clientID, &request_model.UpdateTenderReq{Status: "closed"}, []int64{tender.Version})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Status != "closed" || updated.Version != tender.Version+1 {
+		t.Errorf("got status %q version %d, want closed version %d", updated.Status, updated.Version, tender.Version+1)
+	}
+	if n := s.outboxEvents(model.EventTenderStatusChanged); n != 1 {
+		t.Errorf("%d tender_status_changed events, want 1", n)
+	}
+
+	notifications, listErr := s.store.Notifications().ListByUser(contractorID)
+	if listErr != nil {
+		t.Fatalf("list notifications: %v", listErr)
+	}
+	if len(notifications) != 1 || notifications[0].EventType != model.EventTenderStatusChanged {
+		t.Errorf("bidder notifications = %+v, want one tender_status_changed", notifications)
+	}
+
+	// Closed tenders cannot change any more
+	_, err = s.tenders.UpdateTender(ctx, tender.ID, clientID, &request_model.UpdateTenderReq{Status: "open"}, nil)
+	assertCode(t, err, custom_errors.CodeTenderNotOpen)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	"tender-backend/repository"
+)
+
+// WatchTender subscribes a contractor to the deadline reminders of an open
+// tender. Watching a tender twice is not an error.
+func (t *TenderService) WatchTender(ctx context.Context, tenderID, contractorID int64) *custom_errors.AppError {
+	tender, err := t.GetTenderById(ctx, tenderID)
+	if err != nil {
+		return err
+	}
+
+	if tender.Status != "open" {
+		return custom_errors.NewBadRequestError("Only open tenders can be watched").WithCode(custom_errors.CodeTenderNotOpen)
+	}
+
+	if err := t.store.WithContext(ctx).Tenders().Watch(tenderID, contractorID); err != nil {
+		return custom_errors.NewAppError(err)
+	}
+
+	return nil
+}
+
+func (t *TenderService) UnwatchTender(ctx context.Context, tenderID, contractorID int64) *custom_errors.AppError {
+	if err := t.store.WithContext(ctx).Tenders().Unwatch(tenderID, contractorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return custom_errors.NewNotFoundError("Tender is not watched")
+		}
+		return custom_errors.NewAppError(err)
+	}
+
+	return nil
+}
+
+// GetWatchedTenders returns the tenders a contractor watches.
+func (t *TenderService) GetWatchedTenders(ctx context.Context, contractorID int64) ([]model.Tender, *custom_errors.AppError) {
+	tenders, err := t.store.WithContext(ctx).Tenders().ListWatched(contractorID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return tenders, nil
+}
@@ -1,92 +1,167 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"tender-backend/config"
+	"tender-backend/custom_errors"
 	"tender-backend/model"
 	request_model "tender-backend/model/request"
-
-	"gorm.io/gorm"
+	"tender-backend/notification_template"
+	"tender-backend/repository"
+	"tender-backend/validation"
 )
 
 type UserService struct {
-	db *gorm.DB
+	store repository.Store
 }
 
-func NewUserService(db *gorm.DB) *UserService {
+func NewUserService(store repository.Store) *UserService {
 	return &UserService{
-		db: db,
+		store: store,
 	}
 }
 
-func (s *UserService) CreateUser(user *request_model.CreateUserReq) (*model.User, error) {
+// CreateUser validates the request and stores the user with a hash of the
+// password.
+func (s *UserService) CreateUser(ctx context.Context, user *request_model.CreateUserReq) (*model.User, *custom_errors.AppError) {
+	if err := validation.Struct(user); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := config.HashPassword(user.Password)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	locale := user.Locale
+	if locale == "" {
+		locale = notification_template.DefaultLocale
+	}
+
 	newUser := model.User{
 		FullName: user.FullName,
-		Password: user.Password,
+		Password: hashedPassword,
 		Email:    user.Email,
 		Role:     user.Role,
 		Username: user.Username,
+		Locale:   locale,
 	}
 
-	if _, err := s.GetByUsername(user.Email); err == nil {
-		return nil, errors.New("Email already exists")
+	if _, err := s.GetByUsername(ctx, user.Email); err == nil {
+		return nil, custom_errors.NewConflictError(custom_errors.CodeEmailTaken, "Email already exists")
 	}
 
-	if err := s.db.Create(&newUser).Error; err != nil {
-		return nil, err
+	if err := s.store.WithContext(ctx).Users().Create(&newUser); err != nil {
+		return nil, custom_errors.NewAppError(err)
 	}
 
 	return &newUser, nil
 }
 
-func (s *UserService) GetUserByID(id int64) (*model.User, error) {
-	var user model.User
-	if err := s.db.First(&user, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
-		}
+// Authenticate returns the user whose username and password are given.
+func (s *UserService) Authenticate(ctx context.Context, req *request_model.LoginUserReq) (*model.User, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetByUsername(ctx, req.Username)
+	if err != nil {
+		slog.InfoContext(ctx, "Login of unknown user", "username", req.Username, "error", err)
 		return nil, err
 	}
 
-	return &user, nil
+	if !config.CheckPasswordHash(req.Password, user.Password) {
+		return nil, custom_errors.New(http.StatusUnauthorized, custom_errors.CodeInvalidCredentials, "Invalid username or password")
+	}
+
+	return user, nil
 }
 
-func (s *UserService) GetByUsername(email string) (*model.User, error) {
-	var user model.User
-	if err := s.db.Where("username = ?", email).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+func (s *UserService) GetUserByID(ctx context.Context, id int64) (*model.User, *custom_errors.AppError) {
+	user, err := s.store.WithContext(ctx).Users().GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errUserNotFound
 		}
-		return nil, err
+		return nil, custom_errors.NewAppError(err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
-func (s *UserService) UpdateUser(user *request_model.UpdateUserReq, id int64) (*model.User, error) {
-	var existingUser model.User
-	if err := s.db.First(&existingUser, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+func (s *UserService) GetByUsername(ctx context.Context, email string) (*model.User, *custom_errors.AppError) {
+	user, err := s.store.WithContext(ctx).Users().GetByUsername(email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errUserNotFound
 		}
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return user, nil
+}
+
+func (s *UserService) UpdateUser(ctx context.Context, user *request_model.UpdateUserReq, id int64) (*model.User, *custom_errors.AppError) {
+	if err := validation.Struct(user); err != nil {
 		return nil, err
 	}
 
+	existingUser, err := s.store.WithContext(ctx).Users().GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errUserNotFound
+		}
+		return nil, custom_errors.NewAppError(err)
+	}
+
 	existingUser.FullName = user.FullName
 	existingUser.Email = user.Email
+	if user.Locale != "" {
+		existingUser.Locale = user.Locale
+	}
+
+	if err := s.store.WithContext(ctx).Users().Update(existingUser); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return existingUser, nil
+}
 
-	if err := s.db.Save(&existingUser).Error; err != nil {
+// SetOrganization assigns the user to an organization, whose members share
+// the quotas of organization rate limit policies. Users cannot choose their
+// organization, so only administrators call it. Tokens issued before keep the
+// old organization until they expire.
+func (s *UserService) SetOrganization(ctx context.Context, id int64, req *request_model.SetOrganizationReq) (*model.User, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
 		return nil, err
 	}
 
-	return &existingUser, nil
+	user, err := s.store.WithContext(ctx).Users().GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errUserNotFound
+		}
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	user.Organization = strings.TrimSpace(req.Organization)
+	if err := s.store.WithContext(ctx).Users().Update(user); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return user, nil
 }
 
-func (s *UserService) DeleteUser(id int64) error {
-	if err := s.db.Delete(&model.User{}, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
+func (s *UserService) DeleteUser(ctx context.Context, id int64) *custom_errors.AppError {
+	if err := s.store.WithContext(ctx).Users().Delete(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errUserNotFound
 		}
-		return err
+		return custom_errors.NewAppError(err)
 	}
 
 	return nil
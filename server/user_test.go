@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"tender-backend/custom_errors"
+	request_model "tender-backend/model/request"
+	"testing"
+)
+
+func TestSetOrganization(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	users := NewUserService(s.store)
+	userID := s.createUser(t, "client")
+
+	user, err := users.SetOrganization(ctx, userID, &request_model.SetOrganizationReq{Organization: " Acme "})
+	if err != nil {
+		t.Fatalf("set organization: %v", err)
+	}
+	if user.Organization != "Acme" {
+		t.Errorf("organization = %q, want Acme", user.Organization)
+	}
+
+	stored, getErr := s.store.Users().GetByID(userID)
+	if getErr != nil {
+		t.Fatalf("get user: %v", getErr)
+	}
+	if stored.Organization != "Acme" {
+		t.Errorf("stored organization = %q, want Acme", stored.Organization)
+	}
+
+	_, err = users.SetOrganization(ctx, 999, &request_model.SetOrganizationReq{Organization: "Acme"})
+	assertCode(t, err, custom_errors.CodeUserNotFound)
+}
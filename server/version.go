@@ -0,0 +1,25 @@
+package server
+
+import (
+	"tender-backend/custom_errors"
+)
+
+var (
+	errTenderModified = custom_errors.NewPreconditionFailedError("Tender was modified, fetch it again and retry")
+	errBidModified    = custom_errors.NewPreconditionFailedError("Bid was modified, fetch it again and retry")
+)
+
+// matchesVersion reports whether version is one of the versions a client sent
+// in If-Match. No versions means the client accepts any version.
+func matchesVersion(versions []int64, version int64) bool {
+	if len(versions) == 0 {
+		return true
+	}
+
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	response_model "tender-backend/model/response"
+	"tender-backend/repository"
+	"tender-backend/safe_http"
+	"tender-backend/validation"
+	"time"
+)
+
+const (
+	// Headers sent with every webhook delivery. Receivers should verify the
+	// signature, reject timestamps older than a few minutes and ignore event
+	// IDs they have already processed. A replayed delivery carries the event
+	// ID of the original one and a new delivery ID, so receivers that want to
+	// process replays again should deduplicate on the delivery ID instead.
+	WebhookSignatureHeader  = safe_http.SignatureHeader
+	WebhookEventHeader      = "X-Tender-Event"
+	WebhookEventIDHeader    = "X-Tender-Event-ID"
+	WebhookDeliveryIDHeader = "X-Tender-Delivery-ID"
+	WebhookTimestampHeader  = safe_http.TimestampHeader
+
+	webhookMaxAttempts            = 8
+	webhookMaxConsecutiveFailures = 10
+	webhookBaseBackoff            = 30 * time.Second
+	webhookDeliveryBatchSize      = 100
+	// webhookLease must outlast one attempt, bounded by the client timeout.
+	webhookLease = time.Minute
+)
+
+// TenderAwardedEvent is the data of a tender_awarded event.
+type TenderAwardedEvent struct {
+	Tender model.Tender `json:"tender"`
+	BidID  int64        `json:"bid_id"`
+}
+
+type WebhookService struct {
+	store repository.Store
+	// client refuses internal addresses, as endpoint URLs are user-supplied.
+	client *http.Client
+}
+
+func NewWebhookService(store repository.Store) *WebhookService {
+	return &WebhookService{
+		store:  store,
+		client: safe_http.NewClient(10 * time.Second),
+	}
+}
+
+// CreateEndpoint registers a webhook endpoint and generates its signing secret.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, userID int64, req *request_model.CreateWebhookEndpointReq) (*response_model.CreateWebhookEndpointRes, *custom_errors.AppError) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	endpoint := model.WebhookEndpoint{
+		UserID:     userID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: strings.Join(req.EventTypes, ","),
+		IsActive:   true,
+	}
+
+	if err := s.store.WithContext(ctx).Webhooks().CreateEndpoint(&endpoint); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return &response_model.CreateWebhookEndpointRes{
+		ID:         endpoint.ID,
+		URL:        endpoint.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+	}, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context, userID int64) ([]model.WebhookEndpoint, *custom_errors.AppError) {
+	endpoints, err := s.store.WithContext(ctx).Webhooks().ListEndpoints(userID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return endpoints, nil
+}
+
+// getEndpoint returns the endpoint if it belongs to the user.
+func (s *WebhookService) getEndpoint(ctx context.Context, endpointID, userID int64) (*model.WebhookEndpoint, *custom_errors.AppError) {
+	endpoint, err := s.store.WithContext(ctx).Webhooks().GetEndpoint(endpointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, custom_errors.NewNotFoundError("Webhook not found or access denied")
+		}
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	if endpoint.UserID != userID {
+		return nil, custom_errors.NewNotFoundError("Webhook not found or access denied")
+	}
+
+	return endpoint, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, endpointID, userID int64) *custom_errors.AppError {
+	endpoint, err := s.getEndpoint(ctx, endpointID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		return tx.Webhooks().DeleteEndpoint(endpoint.ID)
+	}); err != nil {
+		return custom_errors.NewAppError(err)
+	}
+
+	return nil
+}
+
+// EnableEndpoint re-activates an endpoint that was disabled after repeated failures.
+func (s *WebhookService) EnableEndpoint(ctx context.Context, endpointID, userID int64) (*model.WebhookEndpoint, *custom_errors.AppError) {
+	endpoint, err := s.getEndpoint(ctx, endpointID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.IsActive = true
+	endpoint.ConsecutiveFailures = 0
+	endpoint.DisabledAt = nil
+
+	if err := s.store.WithContext(ctx).Webhooks().UpdateEndpointState(endpoint); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return endpoint, nil
+}
+
+// GetDeliveries returns the delivery log of an endpoint, newest first.
+func (s *WebhookService) GetDeliveries(ctx context.Context, endpointID, userID int64) ([]model.WebhookDelivery, *custom_errors.AppError) {
+	if _, err := s.getEndpoint(ctx, endpointID, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.store.WithContext(ctx).Webhooks().ListDeliveries(endpointID)
+	if err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDelivery queues the payload of a past delivery again as a new delivery.
+// The replay keeps the event ID of the original, so receivers recognise the event.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, endpointID, deliveryID, userID int64) (*model.WebhookDelivery, *custom_errors.AppError) {
+	if _, err := s.getEndpoint(ctx, endpointID, userID); err != nil {
+		return nil, err
+	}
+
+	original, err := s.store.WithContext(ctx).Webhooks().GetDelivery(deliveryID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, custom_errors.NewAppError(err)
+	}
+	if err != nil || original.EndpointID != endpointID {
+		return nil, custom_errors.NewNotFoundError("Delivery not found")
+	}
+
+	replay := model.WebhookDelivery{
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        model.WebhookStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	if err := s.store.WithContext(ctx).Webhooks().CreateDelivery(&replay); err != nil {
+		return nil, custom_errors.NewAppError(err)
+	}
+
+	return &replay, nil
+}
+
+// RunDeliveryWorker periodically sends pending deliveries whose retry time has
+// come, until ctx is done.
+func (s *WebhookService) RunDeliveryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// A round that started is finished, so no delivery is cut off midway.
+			if err := s.deliverPending(context.WithoutCancel(ctx), now); err != nil {
+				slog.ErrorContext(ctx, "Failed to deliver webhooks", "error", err)
+			}
+		}
+	}
+}
+
+// deliverPending claims and sends due deliveries one at a time, so replicas
+// share the work and each lease only has to cover a single attempt.
+func (s *WebhookService) deliverPending(ctx context.Context, now time.Time) error {
+	for i := 0; i < webhookDeliveryBatchSize; i++ {
+		delivery, err := s.store.WithContext(ctx).Webhooks().ClaimDue(now, time.Now().Add(webhookLease))
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		endpoint, err := s.store.WithContext(ctx).Webhooks().GetEndpoint(delivery.EndpointID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load webhook endpoint", "endpoint_id", delivery.EndpointID, "error", err)
+			continue
+		}
+
+		s.attempt(ctx, endpoint, delivery, time.Now())
+	}
+
+	return nil
+}
+
+func (s *WebhookService) attempt(ctx context.Context, endpoint *model.WebhookEndpoint, delivery *model.WebhookDelivery, now time.Time) {
+	statusCode, err := s.send(ctx, endpoint, delivery, now)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = statusCode
+
+	if err == nil {
+		delivery.Status = model.WebhookStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = model.WebhookStatusFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		}
+	}
+
+	// Workers may deliver to the same endpoint at once, so its failures are
+	// counted in the store rather than on the endpoint loaded earlier.
+	if err := s.store.WithContext(ctx).Transaction(func(tx repository.Store) error {
+		if err := tx.Webhooks().UpdateDelivery(delivery); err != nil {
+			return err
+		}
+		if delivery.Status == model.WebhookStatusSucceeded {
+			return tx.Webhooks().ResetFailures(endpoint.ID)
+		}
+
+		updated, err := tx.Webhooks().RecordFailure(endpoint.ID, webhookMaxConsecutiveFailures, now)
+		if err != nil {
+			return err
+		}
+		// Only the failure that reached the limit disabled the endpoint
+		if !updated.IsActive && updated.ConsecutiveFailures == webhookMaxConsecutiveFailures {
+			slog.WarnContext(ctx, "Webhook endpoint disabled", "endpoint_id", endpoint.ID, "consecutive_failures", updated.ConsecutiveFailures)
+		}
+		return nil
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, endpoint *model.WebhookEndpoint, delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID)
+	req.Header.Set(WebhookDeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	safe_http.SetSignature(req, endpoint.Secret, []byte(delivery.Payload), now)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload computes the hex HMAC-SHA256 of "<timestamp>.<payload>".
+// Binding the timestamp into the signature stops replays with a fresh timestamp.
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	return safe_http.Sign(secret, timestamp, payload)
+}
+
+// webhookBackoff doubles the wait after each failed attempt: 30s, 1m, 2m, ...
+func webhookBackoff(attempts int) time.Duration {
+	return webhookBaseBackoff * time.Duration(1<<(attempts-1))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
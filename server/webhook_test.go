@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"tender-backend/model"
+	"tender-backend/repository"
+	"testing"
+	"time"
+)
+
+func TestReplayDelivery(t *testing.T) {
+	store := repository.NewMemory()
+	webhooks := NewWebhookService(store)
+	ctx := context.Background()
+
+	var deliveryIDs, eventIDs []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveryIDs = append(deliveryIDs, r.Header.Get(WebhookDeliveryIDHeader))
+		eventIDs = append(eventIDs, r.Header.Get(WebhookEventIDHeader))
+	}))
+	defer receiver.Close()
+	// The receiver listens on loopback, which the production client refuses
+	webhooks.client = receiver.Client()
+
+	endpoint := model.WebhookEndpoint{UserID: 1, URL: receiver.URL, Secret: "secret", EventTypes: model.EventTenderAwarded, IsActive: true}
+	if err := store.Webhooks().CreateEndpoint(&endpoint); err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	original := model.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		EventID:       "event-1",
+		EventType:     model.EventTenderAwarded,
+		Payload:       "{}",
+		Status:        model.WebhookStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := store.Webhooks().CreateDelivery(&original); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+
+	replay, appErr := webhooks.ReplayDelivery(ctx, endpoint.ID, original.ID, 1)
+	if appErr != nil {
+		t.Fatalf("replay: %v", appErr)
+	}
+	if err := webhooks.deliverPending(ctx, time.Now()); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	want := []string{strconv.FormatInt(original.ID, 10), strconv.FormatInt(replay.ID, 10)}
+	if len(deliveryIDs) != 2 || deliveryIDs[0] != want[0] || deliveryIDs[1] != want[1] {
+		t.Errorf("delivery IDs = %v, want %v", deliveryIDs, want)
+	}
+	if len(eventIDs) != 2 || eventIDs[0] != "event-1" || eventIDs[1] != "event-1" {
+		t.Errorf("event IDs = %v, want the original event ID twice", eventIDs)
+	}
+}
+
+func TestClaimDue(t *testing.T) {
+	store := repository.NewMemory()
+	now := time.Now()
+
+	endpoint := model.WebhookEndpoint{UserID: 1, URL: "https://example.com", Secret: "secret", IsActive: true}
+	if err := store.Webhooks().CreateEndpoint(&endpoint); err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	delivery := model.WebhookDelivery{EndpointID: endpoint.ID, EventID: "event-1", Status: model.WebhookStatusPending, NextAttemptAt: now}
+	if err := store.Webhooks().CreateDelivery(&delivery); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+
+	if _, err := store.Webhooks().ClaimDue(now, now.Add(webhookLease)); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	// A claimed delivery is leased to its worker until the lease expires
+	if _, err := store.Webhooks().ClaimDue(now, now.Add(webhookLease)); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second claim: got %v, want ErrNotFound", err)
+	}
+	if _, err := store.Webhooks().ClaimDue(now.Add(webhookLease), now.Add(2*webhookLease)); err != nil {
+		t.Fatalf("claim after the lease: %v", err)
+	}
+}
+
+func TestEndpointFailures(t *testing.T) {
+	store := repository.NewMemory()
+	webhooks := NewWebhookService(store)
+	ctx := context.Background()
+
+	failing := true
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+	webhooks.client = receiver.Client()
+
+	endpoint := model.WebhookEndpoint{UserID: 1, URL: receiver.URL, Secret: "secret", IsActive: true}
+	if err := store.Webhooks().CreateEndpoint(&endpoint); err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	deliver := func() {
+		t.Helper()
+		// Every attempt loads the endpoint before the others record theirs,
+		// as concurrent workers would
+		stale := endpoint
+		delivery := model.WebhookDelivery{EndpointID: endpoint.ID, EventID: "event", Status: model.WebhookStatusPending, NextAttemptAt: time.Now()}
+		if err := store.Webhooks().CreateDelivery(&delivery); err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+		webhooks.attempt(ctx, &stale, &delivery, time.Now())
+	}
+	get := func() *model.WebhookEndpoint {
+		t.Helper()
+		stored, err := store.Webhooks().GetEndpoint(endpoint.ID)
+		if err != nil {
+			t.Fatalf("get endpoint: %v", err)
+		}
+		return stored
+	}
+
+	for i := 0; i < webhookMaxConsecutiveFailures; i++ {
+		deliver()
+	}
+	if stored := get(); stored.IsActive || stored.ConsecutiveFailures != webhookMaxConsecutiveFailures || stored.DisabledAt == nil {
+		t.Fatalf("after %d failures: active %v, failures %d, disabled at %v", webhookMaxConsecutiveFailures, stored.IsActive, stored.ConsecutiveFailures, stored.DisabledAt)
+	}
+
+	// A delivery that was in flight and succeeds leaves the endpoint disabled
+	failing = false
+	deliver()
+	if stored := get(); stored.IsActive || stored.ConsecutiveFailures != webhookMaxConsecutiveFailures {
+		t.Errorf("after a late success: active %v, failures %d, want disabled", stored.IsActive, stored.ConsecutiveFailures)
+	}
+
+	if _, err := webhooks.EnableEndpoint(ctx, endpoint.ID, 1); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	failing = true
+	deliver()
+	failing = false
+	deliver()
+	if stored := get(); !stored.IsActive || stored.ConsecutiveFailures != 0 {
+		t.Errorf("after a success: active %v, failures %d, want active without failures", stored.IsActive, stored.ConsecutiveFailures)
+	}
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters that Setup accepts.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. exporter is otlp, which sends spans over HTTP to the collector
+// at OTEL_EXPORTER_OTLP_ENDPOINT (default localhost:4318), stdout, which
+// prints them, or none, which records nothing. The returned function flushes
+// pending spans and must be called before exiting.
+func Setup(ctx context.Context, exporter, serviceName string) (func(context.Context) error, error) {
+	// Trace context is propagated even without an exporter, so traces are not
+	// broken when only some replicas or services export spans.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(exporter) {
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q, use otlp, stdout or none", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a tracer of the global provider, named after the instrumented package.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer("tender-backend/" + name)
+}
+
+// Inject adds the trace context of ctx to the headers of a broker message and
+// returns them. headers are copied, never modified.
+func Inject(ctx context.Context, headers map[string]string) map[string]string {
+	injected := make(map[string]string, len(headers)+2)
+	for key, value := range headers {
+		injected[key] = value
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(injected))
+	return injected
+}
+
+// Extract returns ctx with the trace context from the headers of a broker message.
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"math"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"tender-backend/notification_template"
+	"tender-backend/safe_http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm/utils"
+)
+
+var (
+	roles          = []string{"client", "contractor"}
+	tenderStatuses = []string{"open", "closed"} // Statuses a client can request, tenders are awarded through AwardTender
+	channels       = []string{model.ChannelEmail, model.ChannelSMS, model.ChannelWebhook}
+	digests        = []string{model.DigestNone, model.DigestDaily, model.DigestWeekly}
+)
+
+// rules are the custom tags usable in validate tags besides the built-in ones.
+var rules = map[string]validator.Func{
+	// future accepts times after now
+	"future": func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		return ok && t.After(time.Now())
+	},
+	// money accepts positive amounts with at most two decimal places
+	"money": func(fl validator.FieldLevel) bool {
+		amount := fl.Field().Float()
+		cents := amount * 100
+		return amount > 0 && !math.IsInf(amount, 0) && math.Abs(cents-math.Round(cents)) < 1e-6
+	},
+	// clock accepts times of day in HH:MM format
+	"clock": func(fl validator.FieldLevel) bool {
+		_, err := time.Parse("15:04", fl.Field().String())
+		return err == nil && len(fl.Field().String()) == 5
+	},
+	// public_url accepts URLs whose host is not localhost or an internal IP.
+	// Host names are checked again when a request is sent to them.
+	"public_url": func(fl validator.FieldLevel) bool {
+		return safe_http.CheckURL(fl.Field().String()) == nil
+	},
+	"role":               in(roles),
+	"tender_status":      in(tenderStatuses),
+	"channel":            in(channels),
+	"digest":             in(digests),
+	"event_type":         in(model.EventTypes),
+	"notification_event": in(model.NotificationEventTypes),
+	"locale":             in(notification_template.SupportedLocales),
+}
+
+// descriptions are the messages of the custom tags.
+var descriptions = map[string]string{
+	"future":             "must be in the future",
+	"money":              "must be a positive amount with at most two decimal places",
+	"clock":              "must be a time of day in HH:MM format",
+	"public_url":         "must not point to a local or internal address",
+	"role":               "must be " + oneOf(roles),
+	"tender_status":      "must be " + oneOf(tenderStatuses),
+	"channel":            "must be " + oneOf(channels),
+	"digest":             "must be " + oneOf(digests),
+	"event_type":         "must be " + oneOf(model.EventTypes),
+	"notification_event": "must be " + oneOf(model.NotificationEventTypes),
+	"locale":             "must be " + oneOf(notification_template.SupportedLocales),
+}
+
+func in(values []string) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return utils.Contains(values, fl.Field().String())
+	}
+}
+
+// structRules check rules spanning several fields of a request.
+var structRules = []struct {
+	fn    validator.StructLevelFunc
+	types []any
+}{
+	{validateSavedSearch, []any{request_model.CreateSavedSearchReq{}}},
+	{validateNotificationSettings, []any{request_model.UpdateNotificationSettingsReq{}}},
+}
+
+func validateSavedSearch(sl validator.StructLevel) {
+	req := sl.Current().Interface().(request_model.CreateSavedSearchReq)
+
+	if len(req.Keywords) == 0 && len(req.Categories) == 0 && req.MinBudget == nil && req.MaxBudget == nil {
+		sl.ReportError(req.Keywords, "keywords", "Keywords", "required_without_all", "categories min_budget max_budget")
+	}
+
+	if req.MinBudget != nil && req.MaxBudget != nil && *req.MinBudget > *req.MaxBudget {
+		sl.ReportError(req.MaxBudget, "max_budget", "MaxBudget", "gtefield", "min_budget")
+	}
+}
+
+func validateNotificationSettings(sl validator.StructLevel) {
+	req := sl.Current().Interface().(request_model.UpdateNotificationSettingsReq)
+
+	if req.QuietHoursStart == "" && req.QuietHoursEnd != "" {
+		sl.ReportError(req.QuietHoursStart, "quiet_hours_start", "QuietHoursStart", "required_with", "quiet_hours_end")
+	}
+	if req.QuietHoursEnd == "" && req.QuietHoursStart != "" {
+		sl.ReportError(req.QuietHoursEnd, "quiet_hours_end", "QuietHoursEnd", "required_with", "quiet_hours_start")
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"tender-backend/custom_errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name, as clients send them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule); err != nil {
+			panic(fmt.Sprintf("register validation %s: %v", tag, err))
+		}
+	}
+	for _, rule := range structRules {
+		v.RegisterStructValidation(rule.fn, rule.types...)
+	}
+
+	return v
+}
+
+// Struct checks req against its validate tags and returns every failed field
+// at once as a validation_failed error, or nil when req is valid.
+func Struct(req any) *custom_errors.AppError {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return custom_errors.NewAppError(err)
+	}
+
+	fields := make([]custom_errors.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, custom_errors.FieldError{
+			Field:   fieldPath(fe),
+			Message: message(fe),
+		})
+	}
+	return custom_errors.NewValidationError(fields...)
+}
+
+// fieldPath is the JSON path of a field relative to the request, such as
+// "channels[1]".
+func fieldPath(fe validator.FieldError) string {
+	_, path, _ := strings.Cut(fe.Namespace(), ".")
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return "is required when " + fe.Param() + " is set"
+	case "required_without_all":
+		return "is required unless " + oneOf(strings.Fields(fe.Param())) + " is set"
+	case "min", "gte":
+		return bound(fe, "at least")
+	case "max", "lte":
+		return bound(fe, "at most")
+	case "gtefield":
+		return "cannot be less than " + fe.Param()
+	case "email":
+		return "must be a valid email address"
+	case "http_url":
+		return "must be an http or https URL"
+	case "timezone":
+		return "must be an IANA time zone such as Europe/Berlin"
+	case "e164":
+		return "must be a phone number in international format such as +998901234567"
+	}
+
+	if description, ok := descriptions[fe.Tag()]; ok {
+		return description
+	}
+	return "is invalid"
+}
+
+// bound describes a min, max, gte or lte rule, which limit the length of
+// strings and slices and the value of numbers.
+func bound(fe validator.FieldError, limit string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", limit, fe.Param())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must have %s %s items", limit, fe.Param())
+	default:
+		return fmt.Sprintf("must be %s %s", limit, fe.Param())
+	}
+}
+
+// oneOf lists values as "a, b or c".
+func oneOf(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
@@ -0,0 +1,155 @@
+package validation
+
+import (
+	"net/http"
+	"reflect"
+	"tender-backend/custom_errors"
+	"tender-backend/model"
+	request_model "tender-backend/model/request"
+	"testing"
+	"time"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        any
+		wantFields []custom_errors.FieldError
+	}{
+		{
+			name: "valid tender",
+			req: request_model.CreateTenderReq{
+				Title:       "Office renovation",
+				Description: "Renovate the second floor",
+				Deadline:    time.Now().Add(24 * time.Hour),
+				Budget:      1500.50,
+			},
+		},
+		{
+			name: "invalid tender",
+			req: request_model.CreateTenderReq{
+				Description: "Renovate the second floor",
+				Deadline:    time.Now().Add(-time.Hour),
+				Budget:      10.001,
+			},
+			wantFields: []custom_errors.FieldError{
+				{Field: "title", Message: "is required"},
+				{Field: "deadline", Message: "must be in the future"},
+				{Field: "budget", Message: "must be a positive amount with at most two decimal places"},
+			},
+		},
+		{
+			name: "bounds by kind",
+			req: request_model.CreateUserReq{
+				FullName: "Jane Doe",
+				Password: "abc",
+				Email:    "not an email",
+				Username: "jane",
+				Role:     "admin",
+			},
+			wantFields: []custom_errors.FieldError{
+				{Field: "password", Message: "must be at least 5 characters long"},
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "role", Message: "must be client or contractor"},
+			},
+		},
+		{
+			name:       "awarded status",
+			req:        request_model.UpdateTenderReq{Status: "awarded"},
+			wantFields: []custom_errors.FieldError{{Field: "status", Message: "must be open or closed"}},
+		},
+		{
+			name: "slice items",
+			req: request_model.SetNotificationPreferenceReq{
+				EventType: model.NotificationEventTypes[0],
+				Channels:  []string{model.ChannelEmail, "pigeon"},
+			},
+			wantFields: []custom_errors.FieldError{{Field: "channels[1]", Message: "must be email, sms or webhook"}},
+		},
+		{
+			name:       "saved search without criteria",
+			req:        request_model.CreateSavedSearchReq{Name: "Renovations"},
+			wantFields: []custom_errors.FieldError{{Field: "keywords", Message: "is required unless categories, min_budget or max_budget is set"}},
+		},
+		{
+			name:       "saved search budget range",
+			req:        request_model.CreateSavedSearchReq{Name: "Renovations", MinBudget: float(500), MaxBudget: float(100)},
+			wantFields: []custom_errors.FieldError{{Field: "max_budget", Message: "cannot be less than min_budget"}},
+		},
+		{
+			name: "saved search empty keyword",
+			req:  request_model.CreateSavedSearchReq{Name: "Renovations", Keywords: []string{"paint", ""}},
+			wantFields: []custom_errors.FieldError{
+				{Field: "keywords[1]", Message: "is required"},
+			},
+		},
+		{
+			name: "valid notification settings",
+			req: request_model.UpdateNotificationSettingsReq{
+				Phone:           "+998901234567",
+				WebhookURL:      "https://hooks.example.com/tenders",
+				QuietHoursStart: "22:00",
+				QuietHoursEnd:   "07:30",
+				TimeZone:        "Asia/Tashkent",
+			},
+		},
+		{
+			name:       "quiet hours end only",
+			req:        request_model.UpdateNotificationSettingsReq{QuietHoursEnd: "07:00"},
+			wantFields: []custom_errors.FieldError{{Field: "quiet_hours_start", Message: "is required when quiet_hours_end is set"}},
+		},
+		{
+			name: "invalid notification settings",
+			req: request_model.UpdateNotificationSettingsReq{
+				Phone:           "901234567",
+				WebhookURL:      "http://169.254.169.254/latest/meta-data",
+				QuietHoursStart: "25:00",
+				TimeZone:        "Mars/Olympus",
+			},
+			wantFields: []custom_errors.FieldError{
+				{Field: "phone", Message: "must be a phone number in international format such as +998901234567"},
+				{Field: "webhook_url", Message: "must not point to a local or internal address"},
+				{Field: "quiet_hours_start", Message: "must be a time of day in HH:MM format"},
+				{Field: "time_zone", Message: "must be an IANA time zone such as Europe/Berlin"},
+				{Field: "quiet_hours_end", Message: "is required when quiet_hours_start is set"},
+			},
+		},
+		{
+			name: "webhook endpoint",
+			req: request_model.CreateWebhookEndpointReq{
+				URL:        "ftp://example.com",
+				EventTypes: []string{model.EventTypes[0], "tender.exploded"},
+			},
+			wantFields: []custom_errors.FieldError{
+				{Field: "url", Message: "must be an http or https URL"},
+				{Field: "event_types[1]", Message: "must be " + oneOf(model.EventTypes)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.req)
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v, fields %v", err, err.Fields)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("no error, want fields %v", tt.wantFields)
+			}
+			if err.StatusCode != http.StatusBadRequest || err.Code != custom_errors.CodeValidationFailed {
+				t.Errorf("error = %d %s, want %d %s", err.StatusCode, err.Code, http.StatusBadRequest, custom_errors.CodeValidationFailed)
+			}
+			if !reflect.DeepEqual(err.Fields, tt.wantFields) {
+				t.Errorf("fields = %v\nwant %v", err.Fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package web_socket
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"tender-backend/gen_proto"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// WebSocket subprotocols a client can request to pick the notification encoding.
+// Clients that request none get the legacy protobuf bytes in text frames.
+const (
+	SubprotocolProto = "notification.v2.proto"
+	SubprotocolJSON  = "notification.v2.json"
+)
+
+// Subprotocols lists the supported subprotocols in order of server preference.
+var Subprotocols = []string{SubprotocolProto, SubprotocolJSON}
+
+// WriteTimeout bounds every write to a client. Notifications are delivered to
+// the clients of an instance one at a time, so a client that stops reading
+// is dropped instead of holding up the delivery to everyone else.
+const WriteTimeout = 10 * time.Second
+
+var jsonMarshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// WebSocketConnection writes notifications to a WebSocket in the encoding of
+// the negotiated subprotocol.
+type WebSocketConnection struct {
+	conn *websocket.Conn
+	mu   sync.Mutex // gorilla connections allow only one concurrent writer
+}
+
+func NewWebSocketConnection(conn *websocket.Conn) *WebSocketConnection {
+	return &WebSocketConnection{conn: conn}
+}
+
+func (c *WebSocketConnection) Send(notification *gen_proto.Notification) error {
+	messageType := websocket.TextMessage
+	var body []byte
+	var err error
+
+	switch c.conn.Subprotocol() {
+	case SubprotocolJSON:
+		body, err = jsonMarshalOptions.Marshal(notification)
+	case SubprotocolProto:
+		messageType = websocket.BinaryMessage
+		body, err = proto.Marshal(notification)
+	default:
+		body, err = proto.Marshal(notification)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	if err := c.conn.WriteMessage(messageType, body); err != nil {
+		// The connection is unusable after a failed write. Closing it ends
+		// the read loop of its handler, which unregisters it.
+		c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// CloseGoingAway starts the closing handshake, telling the client the server
+// is going away so it reconnects, possibly to another replica.
+func (c *WebSocketConnection) CloseGoingAway(timeout time.Duration) error {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	return c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(timeout))
+}
+
+// SSEConnection writes notifications as Server-Sent Events. The event ID is
+// the notification ID, so browsers resume with Last-Event-ID after a reconnect.
+// A write that misses WriteTimeout fails the connection, which cancels the
+// request context and so ends the stream.
+type SSEConnection struct {
+	w          http.ResponseWriter
+	flusher    http.Flusher
+	controller *http.ResponseController
+	mu         sync.Mutex
+}
+
+func NewSSEConnection(w http.ResponseWriter) (*SSEConnection, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported")
+	}
+
+	return &SSEConnection{w: w, flusher: flusher, controller: http.NewResponseController(w)}, nil
+}
+
+// setWriteDeadline bounds the next write, where the writer supports it.
+func (c *SSEConnection) setWriteDeadline() {
+	_ = c.controller.SetWriteDeadline(time.Now().Add(WriteTimeout))
+}
+
+func (c *SSEConnection) Send(notification *gen_proto.Notification) error {
+	body, err := jsonMarshalOptions.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setWriteDeadline()
+	if _, err := fmt.Fprintf(c.w, "id: %d\nevent: notification\ndata: %s\n\n", notification.Id, body); err != nil {
+		return err
+	}
+	c.flusher.Flush()
+	return nil
+}
+
+// Ping writes an SSE comment so proxies do not close an idle stream.
+func (c *SSEConnection) Ping() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setWriteDeadline()
+	if _, err := fmt.Fprint(c.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	c.flusher.Flush()
+	return nil
+}
@@ -2,27 +2,90 @@ package web_socket
 
 import (
 	"errors"
-	"github.com/gorilla/websocket"
+	"log/slog"
 	"sync"
+	"tender-backend/gen_proto"
 )
 
-var clients = make(map[int64]*websocket.Conn) // map[user_id]connection
+var ErrClientOffline = errors.New("client is not online")
+
+// Connection is a live client connection notifications can be pushed to.
+// Both WebSocket and Server-Sent Events connections share this registry.
+type Connection interface {
+	Send(notification *gen_proto.Notification) error
+}
+
+var clients = make(map[int64]map[Connection]struct{}) // map[user_id]connections
 var lock sync.Mutex
 
-func RegisterClient(userID int64, conn *websocket.Conn) {
+// RegisterClient adds a connection and reports whether it is the user's
+// first connection on this instance.
+func RegisterClient(userID int64, conn Connection) bool {
 	lock.Lock()
-	clients[userID] = conn
-	lock.Unlock()
+	defer lock.Unlock()
+
+	first := len(clients[userID]) == 0
+	if first {
+		clients[userID] = make(map[Connection]struct{})
+	}
+	clients[userID][conn] = struct{}{}
+	return first
+}
+
+// UnregisterClient removes a connection and reports whether it was the user's
+// last connection on this instance.
+func UnregisterClient(userID int64, conn Connection) bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	delete(clients[userID], conn)
+	if len(clients[userID]) == 0 {
+		delete(clients, userID)
+		return true
+	}
+	return false
 }
 
-func SendNotification(userID int64, message []byte) error {
+// OnlineUsers returns the users with at least one connection on this instance.
+func OnlineUsers() []int64 {
 	lock.Lock()
-	conn, exists := clients[userID]
+	defer lock.Unlock()
+
+	users := make([]int64, 0, len(clients))
+	for userID := range clients {
+		users = append(users, userID)
+	}
+	return users
+}
+
+// SendNotification pushes the notification to every open connection of its user.
+// It succeeds when at least one connection accepted the notification.
+func SendNotification(notification *gen_proto.Notification) error {
+	lock.Lock()
+	conns := make([]Connection, 0, len(clients[notification.UserId]))
+	for conn := range clients[notification.UserId] {
+		conns = append(conns, conn)
+	}
 	lock.Unlock()
 
-	if exists {
-		return conn.WriteMessage(websocket.TextMessage, message)
+	if len(conns) == 0 {
+		return ErrClientOffline
+	}
+
+	var lastErr error
+	delivered := false
+	for _, conn := range conns {
+		if err := conn.Send(notification); err != nil {
+			slog.Warn("Failed to push notification", "notification_id", notification.Id, "error", err)
+			lastErr = err
+			continue
+		}
+		delivered = true
+	}
+
+	if !delivered {
+		return lastErr
 	}
 
-	return errors.New("client is not online")
+	return nil
 }